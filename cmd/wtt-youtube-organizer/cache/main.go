@@ -0,0 +1,226 @@
+package cache
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/play"
+	"wtt-youtube-organizer/config"
+	"wtt-youtube-organizer/utils"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const example = `
+		{cmd} cache info
+		{cmd} cache ls
+		{cmd} cache rm channel_videos.jsonl
+		{cmd} cache rm --all
+`
+
+// Human readable description of known cache entries
+var descriptions = map[string]string{
+	youtubeparser.ChannelCacheFileName: "WTT channel listing from yt-dlp",
+	play.STREAM_URLS_DIR:               "Video and audio links resolved by yt-dlp for play",
+}
+
+var all bool
+
+// entry is a top level file or folder of the cache folder
+type entry struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+func NewCommand(filters *youtubeparser.Filters) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "cache",
+		Short:   "Inspects and invalidates cached data",
+		Long:    "Inspects and invalidates data cached in " + config.GetCacheDir(),
+		Example: utils.FormatExample.Replace(example),
+		Args:    cobra.MinimumNArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:          "info",
+		Short:        "Prints cache folder, its size and whether channel listing is still fresh",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return info(filters)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:          "ls",
+		Short:        "Lists cache entries with their sizes and ages",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return list()
+		},
+	})
+	rmCmd := &cobra.Command{
+		Use:          "rm [NAME...]",
+		Short:        "Removes cache entries listed by cache ls",
+		Args:         cobra.MinimumNArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all == (len(args) > 0) {
+				return fmt.Errorf("pass either entry names or --all")
+			}
+			return remove(args)
+		},
+	}
+	initRmCmd(rmCmd.Flags())
+	cmd.AddCommand(rmCmd)
+	return cmd
+}
+
+func initRmCmd(flagSet *pflag.FlagSet) {
+	flagSet.BoolVar(&all, "all", false, "Remove all cache entries")
+}
+
+func info(filters *youtubeparser.Filters) error {
+	entries, err := readEntries()
+	if err != nil {
+		return err
+	}
+	var size int64
+	for _, entry := range entries {
+		size += entry.Size
+	}
+	fmt.Printf("Folder: %s\n", config.GetCacheDir())
+	fmt.Printf("Entries: %d, %s\n", len(entries), formatSize(size))
+
+	channelCache, err := os.Stat(youtubeparser.GetChannelCachePath())
+	if os.IsNotExist(err) {
+		fmt.Println("Channel listing: not cached, next command fetches it")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	age := time.Since(channelCache.ModTime())
+	switch {
+	case filters.Refresh || filters.CacheTTL <= 0:
+		fmt.Printf("Channel listing: %s old, not used with --refresh or --cacheTtl 0\n", formatAge(age))
+	case age < filters.CacheTTL:
+		fmt.Printf("Channel listing: %s old, used for another %s with --cacheTtl %s\n", formatAge(age), formatAge(filters.CacheTTL-age), filters.CacheTTL)
+	default:
+		fmt.Printf("Channel listing: %s old, expired with --cacheTtl %s, next command fetches it\n", formatAge(age), filters.CacheTTL)
+	}
+	return nil
+}
+
+func list() error {
+	entries, err := readEntries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("Cache is empty")
+		return nil
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "NAME\tSIZE\tAGE\tDESCRIPTION")
+	for _, entry := range entries {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", entry.Name, formatSize(entry.Size), formatAge(time.Since(entry.ModTime)), descriptions[entry.Name])
+	}
+	return writer.Flush()
+}
+
+func remove(names []string) error {
+	if all {
+		entries, err := readEntries()
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			names = append(names, entry.Name)
+		}
+	}
+	cacheDir := config.GetCacheDir()
+	for _, name := range names {
+		// Names come from cache ls, anything else could point outside of cache folder
+		if name != filepath.Base(name) || name == "." || name == ".." {
+			return fmt.Errorf("invalid cache entry %s", name)
+		}
+		path := filepath.Join(cacheDir, name)
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("no cache entry %s", name)
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("error removing %s: %v", path, err)
+		}
+		fmt.Printf("Removed %s\n", name)
+	}
+	return nil
+}
+
+// readEntries returns top level cache entries. Size and modification time of folders
+// are the total size and the latest modification time of files inside
+func readEntries() ([]entry, error) {
+	cacheDir := config.GetCacheDir()
+	dirEntries, err := os.ReadDir(cacheDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", cacheDir, err)
+	}
+	var entries []entry
+	for _, dirEntry := range dirEntries {
+		current := entry{Name: dirEntry.Name()}
+		err := filepath.WalkDir(filepath.Join(cacheDir, dirEntry.Name()), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				current.Size += info.Size()
+			}
+			if info.ModTime().After(current.ModTime) {
+				current.ModTime = info.ModTime()
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", dirEntry.Name(), err)
+		}
+		entries = append(entries, current)
+	}
+	return entries, nil
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size)
+	for _, suffix := range []string{"KiB", "MiB", "GiB"} {
+		value /= unit
+		if value < unit || suffix == "GiB" {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+	}
+	return ""
+}
+
+func formatAge(age time.Duration) string {
+	if age < time.Minute {
+		return age.Round(time.Second).String()
+	}
+	return age.Round(time.Minute).String()
+}
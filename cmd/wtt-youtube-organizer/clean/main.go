@@ -0,0 +1,142 @@
+package clean
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/play"
+	"wtt-youtube-organizer/config"
+	"wtt-youtube-organizer/utils"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const example = `
+		{cmd} clean --watchedOlderThan 180d
+		{cmd} clean --watchedOlderThan 90d --dryRun
+`
+
+var watchedOlderThan string
+var dryRun bool
+
+func NewCommand(filters *youtubeparser.Filters) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "clean",
+		Short:        "Removes stale files from the config folder",
+		Long:         "Removes watched time of videos which were not played for a long time, except videos still in the latest channel videos or watch later queue, and expired cached stream links",
+		Example:      utils.FormatExample.Replace(example),
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			olderThan, err := utils.ParseDuration(watchedOlderThan)
+			if err != nil {
+				return fmt.Errorf("invalid --watchedOlderThan: %v", err)
+			}
+			if err := cleanWatched(olderThan, filters.Offline); err != nil {
+				return err
+			}
+			return cleanStreamUrls()
+		},
+	}
+	initCmd(cmd.Flags())
+	return cmd
+}
+
+func initCmd(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(&watchedOlderThan, "watchedOlderThan", "180d", "Remove watched time not updated for this long, eg. 180d or 720h")
+	flagSet.BoolVar(&dryRun, "dryRun", false, "Only print files which would be removed")
+}
+
+// cleanWatched removes watched time by its age. Listing has only the latest 200 channel videos,
+// so missing from it doesn't mean the video is gone and age is what decides.
+// Videos still in the listing or watch later queue are kept even when old, because they could be resumed
+func cleanWatched(olderThan time.Duration, offline bool) error {
+	watchedDir := filepath.Join(config.GetProjectConfigDir(), play.WATCHED_DIR)
+	entries, err := os.ReadDir(watchedDir)
+	if os.IsNotExist(err) {
+		fmt.Println("Nothing to clean")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", watchedDir, err)
+	}
+
+	keepIds, err := getKeepIds(offline)
+	if err != nil {
+		return err
+	}
+
+	threshold := time.Now().Add(-olderThan)
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || keepIds[entry.Name()] {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("error reading %s: %v", entry.Name(), err)
+		}
+		if info.ModTime().After(threshold) {
+			continue
+		}
+		path := filepath.Join(watchedDir, entry.Name())
+		if dryRun {
+			fmt.Printf("Would remove %s, last watched %s\n", path, info.ModTime().Format(time.DateOnly))
+			removed++
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("error removing %s: %v", path, err)
+		}
+		removed++
+	}
+	if dryRun {
+		fmt.Printf("Would remove %d of %d watched files\n", removed, len(entries))
+		return nil
+	}
+	fmt.Printf("Removed %d of %d watched files\n", removed, len(entries))
+	return nil
+}
+
+// getKeepIds returns ids of videos in the channel listing, including ones with unparseable titles, and in watch later queue
+func getKeepIds(offline bool) (map[string]bool, error) {
+	videos, err := youtubeparser.FetchWttVideos(&youtubeparser.Filters{DisableAllFilters: true, ShowWatched: true, Raw: true, Offline: offline})
+	if err != nil {
+		return nil, err
+	}
+	keepIds := make(map[string]bool)
+	for _, video := range videos {
+		keepIds[video.ID] = true
+	}
+	watchLater, err := config.LoadWatchLater()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range watchLater {
+		youtubeId, err := youtubeparser.GetYouTubeId(entry.URL)
+		if err != nil {
+			continue
+		}
+		keepIds[youtubeId] = true
+	}
+	return keepIds, nil
+}
+
+func cleanStreamUrls() error {
+	removed, err := play.RemoveExpiredStreamUrls(dryRun)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		for _, path := range removed {
+			fmt.Printf("Would remove %s\n", path)
+		}
+		fmt.Printf("Would remove %d expired stream links\n", len(removed))
+		return nil
+	}
+	fmt.Printf("Removed %d expired stream links\n", len(removed))
+	return nil
+}
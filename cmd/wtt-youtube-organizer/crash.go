@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+	"wtt-youtube-organizer/config"
+	"wtt-youtube-organizer/utils"
+)
+
+const CRASH_DIR = "crash"
+
+// recoverCrash turns panic into crash report in the config folder, so bug reports contain the stack.
+// It must be deferred directly in main
+func recoverCrash() {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	stack := debug.Stack()
+	reportPath, err := writeCrashReport(recovered, stack)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s crashed: %v\n%s\nFailed to write crash report: %v\n", utils.MainCommand, recovered, stack, err)
+		os.Exit(2)
+	}
+	fmt.Fprintf(os.Stderr, "%s crashed: %v\nCrash report saved to %s, please attach it to the bug report\n", utils.MainCommand, recovered, reportPath)
+	os.Exit(2)
+}
+
+func writeCrashReport(recovered any, stack []byte) (string, error) {
+	crashDir := utils.CreateFolderIfNoExist(filepath.Join(config.GetProjectConfigDir(), CRASH_DIR))
+	now := time.Now()
+	reportPath := filepath.Join(crashDir, fmt.Sprintf("crash-%s.txt", now.Format("20060102-150405")))
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "time: %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&report, "panic: %v\n", recovered)
+	fmt.Fprintf(&report, "args: %s\n", strings.Join(os.Args, " "))
+	for _, setting := range utils.BuildSettings() {
+		fmt.Fprintf(&report, "%s: %s\n", setting.Key, setting.Value)
+	}
+	writeConfig(&report)
+	fmt.Fprintf(&report, "\n%s", stack)
+
+	if err := os.WriteFile(reportPath, []byte(sanitize(report.String())), 0644); err != nil {
+		return "", fmt.Errorf("error writing crash report %s: %v", reportPath, err)
+	}
+	return reportPath, nil
+}
+
+// writeConfig adds config paths and saved filter sets. Errors are written into the report,
+// since broken config could be the reason of the crash
+func writeConfig(report *strings.Builder) {
+	fmt.Fprintf(report, "config dir: %s\n", config.GetProjectConfigDir())
+	fmt.Fprintf(report, "cache dir: %s\n", config.GetCacheDir())
+	fmt.Fprintf(report, "mpv scripts dir: %s\n", config.GetMpvScriptsDir())
+	if installedScript, err := config.LoadInstalledMpvScript(); err != nil {
+		fmt.Fprintf(report, "installed mpv script: %v\n", err)
+	} else if installedScript != "" {
+		fmt.Fprintf(report, "installed mpv script: %s\n", installedScript)
+	}
+	filterSets, err := config.LoadFilterSets()
+	if err != nil {
+		fmt.Fprintf(report, "filter sets: %v\n", err)
+		return
+	}
+	out, err := json.Marshal(filterSets)
+	if err != nil {
+		fmt.Fprintf(report, "filter sets: %v\n", err)
+		return
+	}
+	fmt.Fprintf(report, "filter sets: %s\n", out)
+}
+
+// sanitize hides user name from paths, so the report could be attached to a public bug report as is
+func sanitize(report string) string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil || homeDir == "" || homeDir == "/" {
+		return report
+	}
+	return strings.ReplaceAll(report, homeDir, "~")
+}
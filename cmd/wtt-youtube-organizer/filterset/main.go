@@ -0,0 +1,159 @@
+package filterset
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"wtt-youtube-organizer/config"
+	"wtt-youtube-organizer/utils"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const example = `
+		{cmd} filters save weekday-singles --gender MS --full
+		{cmd} show --filterSet weekday-singles
+		{cmd} filters list
+		{cmd} filters delete weekday-singles
+`
+
+// Flag selecting saved filter set. It's never saved into a set itself
+const FILTER_SET_FLAG = "filterSet"
+
+func NewCommand(_ *youtubeparser.Filters) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "filters",
+		Short:   "Manages saved filter sets",
+		Long:    "Manages saved filter sets which could be applied to any command with --filterSet",
+		Example: utils.FormatExample.Replace(example),
+		Args:    cobra.MinimumNArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:          "save NAME",
+		Short:        "Saves filter flags passed to this command under the NAME",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return save(args[0], cmd.InheritedFlags())
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:          "list",
+		Short:        "Lists saved filter sets",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return list()
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:          "delete NAME",
+		Short:        "Deletes saved filter set",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return remove(args[0])
+		},
+	})
+	return cmd
+}
+
+// Apply sets flags from the saved filter set unless they were passed explicitly
+func Apply(flagSet *pflag.FlagSet, name string) error {
+	filterSets, err := config.LoadFilterSets()
+	if err != nil {
+		return err
+	}
+	filterSet, ok := filterSets[name]
+	if !ok {
+		return fmt.Errorf("filter set %s does not exist, check %s filters list", name, utils.MainCommand)
+	}
+	for flagName, value := range filterSet {
+		flag := flagSet.Lookup(flagName)
+		if flag == nil {
+			return fmt.Errorf("filter set %s contains unknown flag --%s", name, flagName)
+		}
+		if flag.Changed {
+			continue
+		}
+		if err := flagSet.Set(flagName, value); err != nil {
+			return fmt.Errorf("filter set %s has invalid value %s for --%s: %v", name, value, flagName, err)
+		}
+	}
+	return nil
+}
+
+func save(name string, flagSet *pflag.FlagSet) error {
+	filterSet := make(map[string]string)
+	flagSet.VisitAll(func(flag *pflag.Flag) {
+		if !flag.Changed || flag.Name == FILTER_SET_FLAG {
+			return
+		}
+		// Slice flags print themselves as [a,b] which can't be parsed back
+		if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+			filterSet[flag.Name] = strings.Join(sliceValue.GetSlice(), ",")
+			return
+		}
+		filterSet[flag.Name] = flag.Value.String()
+	})
+	if len(filterSet) == 0 {
+		return fmt.Errorf("no filter flags passed, eg. %s filters save %s --gender MS", utils.MainCommand, name)
+	}
+	filterSets, err := config.LoadFilterSets()
+	if err != nil {
+		return err
+	}
+	filterSets[name] = filterSet
+	if err := config.SaveFilterSets(filterSets); err != nil {
+		return err
+	}
+	fmt.Printf("Saved filter set %s: %s\n", name, formatFilterSet(filterSet))
+	return nil
+}
+
+func list() error {
+	filterSets, err := config.LoadFilterSets()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(filterSets))
+	for name := range filterSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("%s: %s\n", name, formatFilterSet(filterSets[name]))
+	}
+	return nil
+}
+
+func remove(name string) error {
+	filterSets, err := config.LoadFilterSets()
+	if err != nil {
+		return err
+	}
+	if _, ok := filterSets[name]; !ok {
+		return fmt.Errorf("filter set %s does not exist", name)
+	}
+	delete(filterSets, name)
+	if err := config.SaveFilterSets(filterSets); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted filter set %s\n", name)
+	return nil
+}
+
+func formatFilterSet(filterSet map[string]string) string {
+	var flags []string
+	for flagName, value := range filterSet {
+		flags = append(flags, fmt.Sprintf("--%s=%q", flagName, value))
+	}
+	slices.Sort(flags)
+	return strings.Join(flags, " ")
+}
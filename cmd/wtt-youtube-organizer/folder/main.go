@@ -12,9 +12,12 @@ import (
 
 const example = `
 		{cmd} folder
+		{cmd} folder --launcherType ps1
 `
 
 var saveWatchedTimeMpvScript string
+var sortBy string
+var launcherType string
 
 func NewCommand(filters *youtubeparser.Filters) *cobra.Command {
 	cmd := &cobra.Command{
@@ -34,11 +37,13 @@ func NewCommand(filters *youtubeparser.Filters) *cobra.Command {
 
 func initCmd(flagSet *pflag.FlagSet) {
 	flagSet.StringVar(&saveWatchedTimeMpvScript, "saveWatchedTimeMpvScript", "", "Lua script to save watched time of the youtube video")
+	flagSet.StringVar(&sortBy, "sortBy", foldergenerator.SORT_NONE, "Number launchers in upload order or round folders in bracket order. One of upload, round")
+	flagSet.StringVar(&launcherType, "launcherType", "", "Launcher script type. One of sh, bat, ps1. Native to the current OS by default")
 }
 
 func generateFolders(filters *youtubeparser.Filters) {
 	fmt.Println("Execute wtt-youtube-organizer folder generator")
-	err := foldergenerator.CreateFolders(youtubeparser.FilterWttVideos(filters), saveWatchedTimeMpvScript)
+	err := foldergenerator.CreateFolders(youtubeparser.FilterWttVideos(filters), filters, saveWatchedTimeMpvScript, sortBy, launcherType)
 	if err != nil {
 		fmt.Println(err)
 	}
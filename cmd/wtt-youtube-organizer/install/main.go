@@ -0,0 +1,68 @@
+package install
+
+import (
+	"fmt"
+	"path/filepath"
+	"wtt-youtube-organizer/config"
+	"wtt-youtube-organizer/lua"
+	"wtt-youtube-organizer/utils"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const example = `
+		{cmd} install mpv-script
+		{cmd} install mpv-script --dir ~/.config/mpv-tt/scripts
+`
+
+var mpvScriptsDir string
+
+func NewCommand(_ *youtubeparser.Filters) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "install",
+		Short:   "Installs bundled files",
+		Long:    "Installs bundled files",
+		Example: utils.FormatExample.Replace(example),
+		Args:    cobra.MinimumNArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	mpvScriptCmd := &cobra.Command{
+		Use:          "mpv-script",
+		Short:        "Installs mpv script which saves watched time of the video",
+		Long:         "Installs mpv script which saves watched time of the video into mpv scripts folder, so mpv loads it automatically",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return installMpvScript()
+		},
+	}
+	initCmd(mpvScriptCmd.Flags())
+	cmd.AddCommand(mpvScriptCmd)
+	return cmd
+}
+
+func initCmd(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(&mpvScriptsDir, "dir", config.GetMpvScriptsDir(), "mpv scripts folder")
+}
+
+func installMpvScript() error {
+	utils.CreateFolderIfNoExist(mpvScriptsDir)
+	scriptPath := filepath.Join(mpvScriptsDir, lua.SaveWatchedTimeScriptName)
+	if err := utils.WriteFileAtomic(scriptPath, lua.SaveWatchedTimeScript(), 0644); err != nil {
+		return err
+	}
+	absScriptPath, err := filepath.Abs(scriptPath)
+	if err != nil {
+		return err
+	}
+	// play has to know about script in custom --dir, otherwise it passes bundled script as well and mpv runs both
+	if err := config.SaveInstalledMpvScript(absScriptPath); err != nil {
+		return err
+	}
+	fmt.Printf("Installed %s\n", scriptPath)
+	return nil
+}
@@ -2,9 +2,21 @@ package main
 
 import (
 	"log"
+	"time"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/cache"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/clean"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/filterset"
 	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/folder"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/install"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/parser"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/pipeline"
 	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/play"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/serve"
 	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/show"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/smoke"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/state"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/version"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/watchlater"
 	"wtt-youtube-organizer/utils"
 	youtubeparser "wtt-youtube-organizer/youtube_parser"
 
@@ -13,12 +25,28 @@ import (
 )
 
 var filters youtubeparser.Filters
+var filterSet string
 
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   utils.MainCommand,
 		Short: "CLI for WTT ping pong videos youtube channel",
 		Args:  cobra.MinimumNArgs(0),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if filterSet != "" {
+				if err := filterset.Apply(cmd.Flags(), filterSet); err != nil {
+					return err
+				}
+			}
+			filters.GenderNotSet = !cmd.Flags().Changed("gender")
+			if filters.Raw {
+				filters.DisableAllFilters = true
+			}
+			if filters.NoSpoilers {
+				return youtubeparser.ValidateSpoilerStage(filters.SpoilerStage)
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
@@ -27,20 +55,42 @@ func NewCommand() *cobra.Command {
 	cmd.AddCommand(show.NewCommand(&filters))
 	cmd.AddCommand(folder.NewCommand(&filters))
 	cmd.AddCommand(play.NewCommand(&filters))
+	cmd.AddCommand(pipeline.NewCommand(&filters))
+	cmd.AddCommand(smoke.NewCommand(&filters))
+	cmd.AddCommand(parser.NewCommand(&filters))
+	cmd.AddCommand(state.NewCommand(&filters))
+	cmd.AddCommand(install.NewCommand(&filters))
+	cmd.AddCommand(clean.NewCommand(&filters))
+	cmd.AddCommand(version.NewCommand(&filters))
+	cmd.AddCommand(filterset.NewCommand(&filters))
+	cmd.AddCommand(watchlater.NewCommand(&filters))
+	cmd.AddCommand(serve.NewCommand(&filters))
+	cmd.AddCommand(cache.NewCommand(&filters))
 	return cmd
 }
 
 func initCmd(flagSet *pflag.FlagSet) {
 	flagSet.StringVar(&filters.Tournament, "tour", "", "Tournament name")
-	flagSet.StringVar(&filters.Gender, "gender", "MS", "Tournament name")
+	flagSet.StringVar(&filters.Gender, "gender", "", "Filters by match type: MS, WS, MD, WD, XD or all. All by default")
 	flagSet.StringVar(&filters.Filter, "filter", "", "Filter by anything")
+	flagSet.StringSliceVar(&filters.ExcludeFilter, "excludeFilter", nil, "Excludes videos with any of these terms in the title")
+	flagSet.StringSliceVar(&filters.ExcludeTournament, "excludeTour", nil, "Excludes tournaments with any of these names")
+	flagSet.StringSliceVar(&filters.ExcludePlayer, "excludePlayer", nil, "Excludes matches of any of these players")
 	flagSet.BoolVar(&filters.TodayOnly, "today", false, "filters only today matches")
 	flagSet.BoolVar(&filters.Full, "full", false, "filters only full matches")
 	flagSet.BoolVar(&filters.ShowWatched, "showWatched", true, "shows already watched videos")
 	flagSet.BoolVar(&filters.DisableAllFilters, "nofilters", false, "Disables all filters")
+	flagSet.BoolVar(&filters.Raw, "raw", false, "Keeps videos with unparseable titles. Implies --nofilters")
+	flagSet.StringVar(&filterSet, filterset.FILTER_SET_FLAG, "", "Applies filters saved with filters save. Explicitly passed filters take priority")
+	flagSet.DurationVar(&filters.CacheTTL, "cacheTtl", 30*time.Minute, "Reuse channel videos fetched within this period. 0 disables the cache")
+	flagSet.BoolVar(&filters.Refresh, "refresh", false, "Fetch channel videos even when cached ones are fresh")
+	flagSet.BoolVar(&filters.Offline, "offline", false, "Uses only cached channel videos and local files without network access")
+	flagSet.BoolVar(&filters.NoSpoilers, "noSpoilers", false, "Hides round names after --spoilerStage")
+	flagSet.StringVar(&filters.SpoilerStage, "spoilerStage", "QF", "Last round shown with --noSpoilers. One of Q1, Q2, Q3, R128, R64, R32, R16, QF, SF, F")
 }
 
 func main() {
+	defer recoverCrash()
 	err := NewCommand().Execute()
 	if err != nil {
 		log.Fatalf("Failed to execute command : %v", err)
@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"wtt-youtube-organizer/utils"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const example = `
+		{cmd} parser corpus add --corpus youtube_parser/testdata/title_corpus.jsonl "Ma Long vs Fan Zhendong | MS F | WTT Champions Chongqing 2024"
+		{cmd} parser verify --corpus youtube_parser/testdata/title_corpus.jsonl
+`
+
+var corpusPath string
+
+func NewCommand(_ *youtubeparser.Filters) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "parser",
+		Short:   "Maintains corpus of video titles to catch title format changes",
+		Long:    "Maintains corpus of video titles to catch title format changes",
+		Example: utils.FormatExample.Replace(example),
+		Args:    cobra.MinimumNArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	initCmd(cmd.PersistentFlags())
+	// Corpus lives in the source tree, so there is no default which works outside of the repo root
+	if err := cmd.MarkPersistentFlagRequired("corpus"); err != nil {
+		log.Fatalf("Failed to mark --corpus required: %v", err)
+	}
+
+	corpusCmd := &cobra.Command{
+		Use:   "corpus",
+		Short: "Manages title corpus",
+		Args:  cobra.MinimumNArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	corpusCmd.AddCommand(&cobra.Command{
+		Use:          "add TITLE...",
+		Short:        "Appends titles to corpus together with their current parse result",
+		Long:         "Appends titles to corpus together with their current parse result. Review the result in the corpus file before committing it",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return corpusAdd(args)
+		},
+	})
+	cmd.AddCommand(corpusCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:          "verify",
+		Short:        "Parses all corpus titles and reports ones which differ from expected",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return verify()
+		},
+	})
+	return cmd
+}
+
+func initCmd(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(&corpusPath, "corpus", "", "Path to the title corpus file, eg. youtube_parser/testdata/title_corpus.jsonl")
+}
+
+func corpusAdd(titles []string) error {
+	var entries []youtubeparser.CorpusEntry
+	for _, title := range titles {
+		entry := youtubeparser.NewCorpusEntry(title)
+		fmt.Printf("%s -> %s\n", title, formatParts(entry.Expected))
+		entries = append(entries, entry)
+	}
+	added, err := youtubeparser.AppendCorpus(corpusPath, entries)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added %d titles to %s\n", added, corpusPath)
+	return nil
+}
+
+func verify() error {
+	entries, err := youtubeparser.LoadCorpus(corpusPath)
+	if err != nil {
+		return err
+	}
+	mismatches := youtubeparser.VerifyCorpus(entries)
+	for _, mismatch := range mismatches {
+		fmt.Printf("MISMATCH %s\n  expected: %s\n  actual:   %s\n", mismatch.Entry.Title, formatParts(mismatch.Entry.Expected), formatParts(mismatch.Actual))
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%d of %d corpus titles parsed differently", len(mismatches), len(entries))
+	}
+	fmt.Printf("All %d corpus titles parsed as expected\n", len(entries))
+	return nil
+}
+
+func formatParts(parts *youtubeparser.NameParts) string {
+	if parts == nil {
+		return "rejected"
+	}
+	out, _ := json.Marshal(parts)
+	return string(out)
+}
@@ -0,0 +1,92 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+	"wtt-youtube-organizer/config"
+	foldergenerator "wtt-youtube-organizer/folder_generator"
+	"wtt-youtube-organizer/utils"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const example = `
+		{cmd} pipeline run
+`
+
+const LOCK_FILE_NAME = "pipeline.lock"
+
+var saveWatchedTimeMpvScript string
+var sortBy string
+var launcherType string
+
+func NewCommand(filters *youtubeparser.Filters) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pipeline",
+		Short: "Runs all update steps in one go",
+		Long:  "Runs all update steps in one go. Designed to be the single cron or systemd timer entrypoint",
+		Args:  cobra.MinimumNArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newRunCommand(filters))
+	return cmd
+}
+
+func newRunCommand(filters *youtubeparser.Filters) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "run",
+		Short:        "Fetches new WTT videos and regenerates folder structure",
+		Long:         "Fetches new WTT videos and regenerates folder structure. Skips silently when another run is in progress",
+		Example:      utils.FormatExample.Replace(example),
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(filters)
+		},
+	}
+	initCmd(cmd.Flags())
+	return cmd
+}
+
+func initCmd(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(&saveWatchedTimeMpvScript, "saveWatchedTimeMpvScript", "", "Lua script to save watched time of the youtube video")
+	flagSet.StringVar(&sortBy, "sortBy", foldergenerator.SORT_NONE, "Number launchers in upload order or round folders in bracket order. One of upload, round")
+	flagSet.StringVar(&launcherType, "launcherType", "", "Launcher script type. One of sh, bat, ps1. Native to the current OS by default")
+}
+
+// run returns an error only for failures which need user attention.
+// Overlapping runs are expected from timers and just skipped
+func run(filters *youtubeparser.Filters) error {
+	if filters.Offline {
+		return fmt.Errorf("pipeline fetches new videos: %w", youtubeparser.ErrOffline)
+	}
+	configDir := utils.CreateFolderIfNoExist(config.GetProjectConfigDir())
+	lock, err := utils.TryLock(filepath.Join(configDir, LOCK_FILE_NAME))
+	if errors.Is(err, utils.ErrLocked) {
+		fmt.Println("pipeline: another run is in progress, skipping")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	start := time.Now()
+	// Pipeline is the one keeping the cache fresh for other commands
+	filters.Refresh = true
+	videos, err := youtubeparser.FetchWttVideos(filters)
+	if err != nil {
+		return fmt.Errorf("pipeline: fetch failed: %v", err)
+	}
+	if err := foldergenerator.CreateFolders(videos, filters, saveWatchedTimeMpvScript, sortBy, launcherType); err != nil {
+		return fmt.Errorf("pipeline: folder generation failed: %v", err)
+	}
+	fmt.Printf("pipeline: %d videos, folders updated in %s\n", len(videos), time.Since(start).Round(time.Second))
+	return nil
+}
@@ -0,0 +1,50 @@
+package play
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"wtt-youtube-organizer/config"
+	"wtt-youtube-organizer/utils"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+)
+
+const PLAYING_DIR = "playing"
+
+// getInstancePaths returns per video lock file and mpv ipc socket.
+// Lock is held for the whole lifetime of the mpv instance playing the video
+func getInstancePaths(videoUrl string) (lockPath string, socketPath string, err error) {
+	youtubeId, err := youtubeparser.GetYouTubeId(videoUrl)
+	if err != nil {
+		return "", "", err
+	}
+	configDir := utils.CreateFolderIfNoExist(config.GetProjectConfigDir())
+	playingDir := utils.CreateFolderIfNoExist(filepath.Join(configDir, PLAYING_DIR))
+	return filepath.Join(playingDir, youtubeId+".lock"), getMpvIpcPath(playingDir, youtubeId), nil
+}
+
+// sendMpvCommands sends commands to already running mpv through its json ipc socket
+// See https://mpv.io/manual/stable/#json-ipc
+func sendMpvCommands(socketPath string, commands ...[]any) error {
+	conn, err := dialMpv(socketPath)
+	if err != nil {
+		return fmt.Errorf("error connecting to mpv socket %s: %v", socketPath, err)
+	}
+	defer conn.Close()
+
+	encoder := json.NewEncoder(conn)
+	for _, command := range commands {
+		if err := encoder.Encode(map[string]any{"command": command}); err != nil {
+			return fmt.Errorf("error sending %v to mpv: %v", command, err)
+		}
+	}
+	return nil
+}
+
+// wakeRunningMpv brings attention to the player which already plays the video instead of starting second one
+func wakeRunningMpv(socketPath string) error {
+	return sendMpvCommands(socketPath,
+		[]any{"set_property", "pause", false},
+		[]any{"show-text", "Already playing this video", 3000},
+	)
+}
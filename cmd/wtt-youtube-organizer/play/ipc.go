@@ -0,0 +1,19 @@
+//go:build !windows
+
+package play
+
+import (
+	"io"
+	"net"
+	"path/filepath"
+	"time"
+)
+
+// getMpvIpcPath returns unix socket mpv listens on for json ipc
+func getMpvIpcPath(playingDir string, youtubeId string) string {
+	return filepath.Join(playingDir, youtubeId+".sock")
+}
+
+func dialMpv(socketPath string) (io.WriteCloser, error) {
+	return net.DialTimeout("unix", socketPath, 2*time.Second)
+}
@@ -0,0 +1,16 @@
+package play
+
+import (
+	"io"
+	"os"
+)
+
+// getMpvIpcPath returns named pipe mpv listens on for json ipc, since mpv doesn't use unix sockets on windows
+func getMpvIpcPath(_ string, youtubeId string) string {
+	return `\\.\pipe\wtt-` + youtubeId
+}
+
+// dialMpv opens the named pipe as a file, which is enough to write commands to mpv
+func dialMpv(pipePath string) (io.WriteCloser, error) {
+	return os.OpenFile(pipePath, os.O_RDWR, 0)
+}
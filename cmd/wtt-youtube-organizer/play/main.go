@@ -1,16 +1,17 @@
 package play
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
-	"strconv"
 	"strings"
 	"wtt-youtube-organizer/config"
+	"wtt-youtube-organizer/lua"
 	"wtt-youtube-organizer/shell"
 	"wtt-youtube-organizer/utils"
 	youtubeparser "wtt-youtube-organizer/youtube_parser"
@@ -30,6 +31,8 @@ const FORMAT = "bestvideo[height<=2160]+bestaudio/best"
 
 var videoUrl string
 var saveWatchedTimeMpvScript string
+var speed float64
+var watchLater bool
 
 func NewCommand(filters *youtubeparser.Filters) *cobra.Command {
 	cmd := &cobra.Command{
@@ -40,9 +43,19 @@ func NewCommand(filters *youtubeparser.Filters) *cobra.Command {
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		Run: func(cmd *cobra.Command, args []string) {
-			if videoUrl == "" {
+			if videoUrl == "" && !watchLater {
 				log.Fatalln("--videoUrl arg must be provided with valid youtube url")
 			}
+			if speed <= 0 {
+				log.Fatalln("--speed must be greater than 0")
+			}
+			if filters.Offline {
+				log.Fatalf("play streams video from youtube: %v", youtubeparser.ErrOffline)
+			}
+			if watchLater {
+				playWatchLater(filters.Refresh)
+				return
+			}
 			play(filters)
 		},
 	}
@@ -52,23 +65,75 @@ func NewCommand(filters *youtubeparser.Filters) *cobra.Command {
 
 func initCmd(flagSet *pflag.FlagSet) {
 	flagSet.StringVar(&videoUrl, "videoUrl", "", "Youtube video URL")
-	flagSet.StringVar(&saveWatchedTimeMpvScript, "saveWatchedTimeMpvScript", "", "Lua script to save watched time of the youtube video")
+	flagSet.StringVar(&saveWatchedTimeMpvScript, "saveWatchedTimeMpvScript", "", "Lua script to save watched time of the youtube video. Bundled script is used by default")
+	flagSet.Float64Var(&speed, "speed", 1, "Playback speed, eg. 1.5 to rewatch faster")
+	flagSet.BoolVar(&watchLater, "watchLater", false, "Plays videos from watch later queue one by one removing finished ones")
 }
 
 // plays video/audio links received from yt-dlp directly in mpv
 // mpv is responsible for mixing video and audio together
-func play(_ *youtubeparser.Filters) {
-	videoLink, audioLink := getVideoUrlsFromYtDlp(videoUrl)
-	mpvCmd := runMpv(videoLink, audioLink, false)
-	if err := mpvCmd.Wait(); err != nil {
+func play(filters *youtubeparser.Filters) {
+	playVideo(videoUrl, filters.Refresh)
+}
+
+// playVideo plays the video unless it's already playing. With refresh links cached by previous play are not reused
+func playVideo(videoUrl string, refresh bool) {
+	lockPath, socketPath, err := getInstancePaths(videoUrl)
+	if err != nil {
+		log.Fatalf("Failed to construct player instance files for %s: %v\n", videoUrl, err)
+	}
+	// Second launcher of the same video must not start another mpv
+	// which would overwrite watched time of the first one on exit
+	lock, err := utils.TryLock(lockPath)
+	if errors.Is(err, utils.ErrLocked) {
+		// Socket is missing while first instance still resolves links with yt-dlp
+		if err := wakeRunningMpv(socketPath); err != nil {
+			fmt.Printf("Video %s is already starting in another player: %v\n", videoUrl, err)
+			return
+		}
+		fmt.Printf("Video %s is already playing\n", videoUrl)
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer lock.Unlock()
+
+	videoLink, audioLink, cached, err := getVideoUrls(videoUrl, refresh)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	err = runMpv(videoUrl, videoLink, audioLink, socketPath, false).Wait()
+	if err == nil {
+		return
+	}
+	removeCachedVideoUrls(videoUrl)
+	if !cached {
+		log.Fatal(err)
+	}
+	// Cached links stop working after ip change, eg. when state was imported on another machine
+	fmt.Printf("mpv failed with cached links: %v, retrying with fresh links\n", err)
+	videoLink, audioLink, _, err = getVideoUrls(videoUrl, true)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := runMpv(videoUrl, videoLink, audioLink, socketPath, false).Wait(); err != nil {
+		removeCachedVideoUrls(videoUrl)
 		log.Fatal(err)
 	}
 }
 
-func runMpv(directVideoLink string, directAudioLink string, verbose bool) *exec.Cmd {
+func runMpv(videoUrl string, directVideoLink string, directAudioLink string, ipcSocket string, verbose bool) *exec.Cmd {
 	args := []string{"--no-resume-playback", "--player-operation-mode=pseudo-gui"}
-	if saveWatchedTimeMpvScript != "" {
-		args = append(args, fmt.Sprintf("--script=%s", saveWatchedTimeMpvScript))
+	if ipcSocket != "" {
+		args = append(args, fmt.Sprintf("--input-ipc-server=%s", ipcSocket))
+	}
+	watchedTimeScript, err := getSaveWatchedTimeScript()
+	if err != nil {
+		log.Fatalf("Failed to prepare mpv script to save watched time: %v", err)
+	}
+	if watchedTimeScript != "" {
+		args = append(args, fmt.Sprintf("--script=%s", watchedTimeScript))
 	}
 
 	if directAudioLink != "" {
@@ -85,6 +150,9 @@ func runMpv(directVideoLink string, directAudioLink string, verbose bool) *exec.
 	if watchedSeconds > 0 {
 		args = append(args, fmt.Sprintf("--start=%d", watchedSeconds))
 	}
+	if speed != 1 {
+		args = append(args, fmt.Sprintf("--speed=%g", speed))
+	}
 	if verbose {
 		args = append(args, "-v")
 	}
@@ -167,32 +235,60 @@ func runMpv(directVideoLink string, directAudioLink string, verbose bool) *exec.
 	return mpvCmd
 }
 
+// Returns mpv script to pass with --script arg.
+// Script from --saveWatchedTimeMpvScript has priority, then script installed by install mpv-script into any folder which mpv loads itself,
+// installed copy older than the bundled script is reported. Otherwise bundled script is written to the config folder
+func getSaveWatchedTimeScript() (string, error) {
+	if saveWatchedTimeMpvScript != "" {
+		return saveWatchedTimeMpvScript, nil
+	}
+	installedScript, err := config.LoadInstalledMpvScript()
+	if err != nil {
+		return "", err
+	}
+	// Default folder covers scripts installed before their location was remembered, copied by hand
+	// or remembered on another machine and imported with state import
+	for _, scriptPath := range []string{installedScript, filepath.Join(config.GetMpvScriptsDir(), lua.SaveWatchedTimeScriptName)} {
+		if scriptPath == "" {
+			continue
+		}
+		installed, err := os.ReadFile(scriptPath)
+		if err != nil {
+			continue
+		}
+		// mpv loads installed script anyway, passing bundled one as well would run both
+		if !bytes.Equal(installed, lua.SaveWatchedTimeScript()) {
+			fmt.Fprintf(os.Stderr, "Warning: %s differs from the bundled script, run %s install mpv-script to update it\n", scriptPath, utils.MainCommand)
+		}
+		return "", nil
+	}
+	configDir := utils.CreateFolderIfNoExist(config.GetProjectConfigDir())
+	scriptPath := filepath.Join(configDir, lua.SaveWatchedTimeScriptName)
+	if err := utils.WriteFileAtomic(scriptPath, lua.SaveWatchedTimeScript(), 0644); err != nil {
+		return "", err
+	}
+	return scriptPath, nil
+}
+
 // Gets the amount of watched seconds for the given watchedFileName
 // returns 0 if file was not watched yet
 //
 // watchedFileName named as last part of youtube video
 // https://www.youtube.com/watch?v=OdXQDJOQ27w -> becomes OdXQDJOQ27w
 func getCurrentWatchedTime(watchedFileName string) (uint32, error) {
-	// Read file contents
-	data, err := os.ReadFile(watchedFileName)
+	watchedTime, err := ReadWatchedTime(watchedFileName)
 	if err != nil {
 		// Valid case. Watching video first time
 		if os.IsNotExist(err) {
 			return 0, nil
-		} else {
-			return 0, fmt.Errorf("error reading file %s: %v", watchedFileName, err)
 		}
+		return 0, err
 	}
-	numberStr := strings.TrimSpace(string(data))
-	number, err := strconv.ParseUint(numberStr, 10, 32)
-	if err != nil {
-		return 0, fmt.Errorf("error parsing watched seconds %s from %s: %v", numberStr, watchedFileName, err)
-	}
-	return uint32(number), nil
+	return uint32(watchedTime.Seconds), nil
 }
 
 func getWatchedFileName(videoUrl string) (string, error) {
-	youtubeId, err := getYouTubeId(videoUrl)
+	youtubeId, err := youtubeparser.GetYouTubeId(videoUrl)
 	configDir := utils.CreateFolderIfNoExist(config.GetProjectConfigDir())
 	watchedDir := utils.CreateFolderIfNoExist(filepath.Join(configDir, WATCHED_DIR))
 
@@ -203,23 +299,14 @@ func getWatchedFileName(videoUrl string) (string, error) {
 
 }
 
-func getYouTubeId(videoUrl string) (string, error) {
-	re := regexp.MustCompile(`(?:v=|/)([0-9A-Za-z_-]{11}).*`)
-	matches := re.FindStringSubmatch(videoUrl)
-	if len(matches) < 2 {
-		return "", fmt.Errorf("invalid YouTube URL")
-	}
-	return matches[1], nil
-}
-
 // Just get video and audio url from ytdlp without downloading or mixing them
-func getVideoUrlsFromYtDlp(youtubeUrl string) (videoLink string, audioLink string) {
+func GetVideoUrlsFromYtDlp(youtubeUrl string) (videoLink string, audioLink string, err error) {
 	args := []string{"-f", FORMAT, "--get-url"}
 	args = append(args, youtubeUrl)
 	out := shell.ExecuteScript("yt-dlp", args...)
 
 	if out.Err != "" {
-		log.Fatalf("Error executing shell command: %s", out.Err)
+		return "", "", fmt.Errorf("error executing shell command: %s", out.Err)
 	}
 	for _, link := range strings.Split(out.Out, "\n") {
 		if link == "" {
@@ -234,5 +321,5 @@ func getVideoUrlsFromYtDlp(youtubeUrl string) (videoLink string, audioLink strin
 			audioLink = link
 		}
 	}
-	return videoLink, audioLink
+	return videoLink, audioLink, nil
 }
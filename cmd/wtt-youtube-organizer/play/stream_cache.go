@@ -0,0 +1,130 @@
+package play
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+	"wtt-youtube-organizer/config"
+	"wtt-youtube-organizer/utils"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+)
+
+// STREAM_URLS_DIR keeps video and audio links resolved by yt-dlp per youtube id in the cache folder
+const STREAM_URLS_DIR = "stream_urls"
+
+// Links are not reused when they expire sooner, so mpv doesn't lose the stream in the middle of the match
+const STREAM_URL_MIN_VALIDITY = 30 * time.Minute
+
+type streamUrls struct {
+	Video  string    `json:"video"`
+	Audio  string    `json:"audio"`
+	Expire time.Time `json:"expire"`
+}
+
+func getStreamUrlsPath(videoUrl string) (string, error) {
+	youtubeId, err := youtubeparser.GetYouTubeId(videoUrl)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(config.GetCacheDir(), STREAM_URLS_DIR, youtubeId+".json"), nil
+}
+
+// getVideoUrls returns links cached by previous play while they are valid, otherwise resolves them with yt-dlp.
+// Resolving takes several seconds, which is noticeable when player is restarted quickly.
+// cached tells whether links came from the cache
+func getVideoUrls(videoUrl string, refresh bool) (videoLink string, audioLink string, cached bool, err error) {
+	cachePath, err := getStreamUrlsPath(videoUrl)
+	if err != nil {
+		return "", "", false, err
+	}
+	if !refresh {
+		var cachedUrls streamUrls
+		if err := utils.LoadJSON(cachePath, &cachedUrls); err == nil {
+			if time.Until(cachedUrls.Expire) > STREAM_URL_MIN_VALIDITY {
+				return cachedUrls.Video, cachedUrls.Audio, true, nil
+			}
+			removeCachedVideoUrls(videoUrl)
+		}
+	}
+	videoLink, audioLink, err = GetVideoUrlsFromYtDlp(videoUrl)
+	if err != nil {
+		return "", "", false, err
+	}
+	expire, ok := getLinksExpire(videoLink, audioLink)
+	if !ok {
+		return videoLink, audioLink, false, nil
+	}
+	// Failed cache write only makes the next start slower
+	if err := utils.SaveJSON(cachePath, streamUrls{Video: videoLink, Audio: audioLink, Expire: expire}); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save stream urls cache: %v\n", err)
+	}
+	return videoLink, audioLink, false, nil
+}
+
+// removeCachedVideoUrls drops cached links, eg. when mpv failed to play them after ip change
+func removeCachedVideoUrls(videoUrl string) {
+	cachePath, err := getStreamUrlsPath(videoUrl)
+	if err != nil {
+		return
+	}
+	if err := os.Remove(cachePath); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Failed to remove stream urls cache: %v\n", err)
+	}
+}
+
+// RemoveExpiredStreamUrls removes cached links which can't be reused anymore,
+// since links of videos which are not played again are never read and removed by play.
+// Returns paths of removed files, with dryRun files are only listed
+func RemoveExpiredStreamUrls(dryRun bool) ([]string, error) {
+	streamUrlsDir := filepath.Join(config.GetCacheDir(), STREAM_URLS_DIR)
+	entries, err := os.ReadDir(streamUrlsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", streamUrlsDir, err)
+	}
+	var removed []string
+	for _, entry := range entries {
+		path := filepath.Join(streamUrlsDir, entry.Name())
+		var cached streamUrls
+		// Unreadable entries are useless for play as well
+		if err := utils.LoadJSON(path, &cached); err == nil && time.Until(cached.Expire) > STREAM_URL_MIN_VALIDITY {
+			continue
+		}
+		if !dryRun {
+			if err := os.Remove(path); err != nil {
+				return removed, fmt.Errorf("error removing %s: %v", path, err)
+			}
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+// getLinksExpire returns the earliest expire query param of googlevideo links.
+// Links without expire param are not cached since their validity is unknown
+func getLinksExpire(links ...string) (time.Time, bool) {
+	var expire time.Time
+	for _, link := range links {
+		if link == "" {
+			continue
+		}
+		parsed, err := url.Parse(link)
+		if err != nil {
+			return time.Time{}, false
+		}
+		seconds, err := strconv.ParseInt(parsed.Query().Get("expire"), 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		linkExpire := time.Unix(seconds, 0)
+		if expire.IsZero() || linkExpire.Before(expire) {
+			expire = linkExpire
+		}
+	}
+	return expire, !expire.IsZero()
+}
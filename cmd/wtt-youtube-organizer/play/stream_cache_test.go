@@ -0,0 +1,95 @@
+package play
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"wtt-youtube-organizer/config"
+	"wtt-youtube-organizer/utils"
+)
+
+func TestGetLinksExpire(t *testing.T) {
+	tests := []struct {
+		name   string
+		links  []string
+		want   int64
+		wantOk bool
+	}{
+		{name: "single link", links: []string{"https://x.googlevideo.com/v?expire=1700000000&ip=1.2.3.4"}, want: 1700000000, wantOk: true},
+		{name: "earliest link wins", links: []string{"https://x.googlevideo.com/v?expire=1700000500", "https://x.googlevideo.com/a?expire=1700000100"}, want: 1700000100, wantOk: true},
+		{name: "missing audio link", links: []string{"https://x.googlevideo.com/v?expire=1700000000", ""}, want: 1700000000, wantOk: true},
+		{name: "missing expire", links: []string{"https://x.googlevideo.com/v?ip=1.2.3.4"}},
+		{name: "one link without expire", links: []string{"https://x.googlevideo.com/v?expire=1700000000", "https://x.googlevideo.com/a"}},
+		{name: "malformed expire", links: []string{"https://x.googlevideo.com/v?expire=soon"}},
+		{name: "malformed url", links: []string{"https://x.googlevideo.com/v?expire=1700000000", "://bad"}},
+		{name: "no links", links: []string{"", ""}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := getLinksExpire(test.links...)
+			if ok != test.wantOk {
+				t.Fatalf("getLinksExpire() ok = %v, want %v", ok, test.wantOk)
+			}
+			if ok && got.Unix() != test.want {
+				t.Errorf("getLinksExpire() = %d, want %d", got.Unix(), test.want)
+			}
+		})
+	}
+}
+
+func saveStreamUrls(t *testing.T, youtubeId string, expire time.Time) {
+	t.Helper()
+	path := filepath.Join(config.GetCacheDir(), STREAM_URLS_DIR, youtubeId+".json")
+	if err := utils.SaveJSON(path, streamUrls{Video: "video-" + youtubeId, Audio: "audio-" + youtubeId, Expire: expire}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStreamUrlsValidity(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	now := time.Now()
+	entries := []struct {
+		youtubeId string
+		expire    time.Time
+		reused    bool
+	}{
+		{youtubeId: "validvideo1", expire: now.Add(2 * time.Hour), reused: true},
+		{youtubeId: "almostgone1", expire: now.Add(STREAM_URL_MIN_VALIDITY - time.Minute)},
+		{youtubeId: "expiredvid1", expire: now.Add(-time.Hour)},
+	}
+	for _, entry := range entries {
+		saveStreamUrls(t, entry.youtubeId, entry.expire)
+	}
+	brokenPath := filepath.Join(config.GetCacheDir(), STREAM_URLS_DIR, "brokenvideo.json")
+	if err := os.WriteFile(brokenPath, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only valid links are reused without yt-dlp
+	video, audio, cached, err := getVideoUrls("https://www.youtube.com/watch?v=validvideo1", false)
+	if err != nil || !cached || video != "video-validvideo1" || audio != "audio-validvideo1" {
+		t.Errorf("getVideoUrls() = %q, %q, %v, %v, want cached links", video, audio, cached, err)
+	}
+
+	removed, err := RemoveExpiredStreamUrls(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 3 {
+		t.Errorf("RemoveExpiredStreamUrls(dryRun) = %v, want 3 files", removed)
+	}
+	if _, err := RemoveExpiredStreamUrls(false); err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		_, err := os.Stat(filepath.Join(config.GetCacheDir(), STREAM_URLS_DIR, entry.youtubeId+".json"))
+		if exists := err == nil; exists != entry.reused {
+			t.Errorf("%s exists = %v, want %v", entry.youtubeId, exists, entry.reused)
+		}
+	}
+	if _, err := os.Stat(brokenPath); !os.IsNotExist(err) {
+		t.Errorf("unreadable %s was not removed", brokenPath)
+	}
+}
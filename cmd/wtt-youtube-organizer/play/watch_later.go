@@ -0,0 +1,104 @@
+package play
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"time"
+	"wtt-youtube-organizer/config"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+)
+
+// Video is considered finished and removed from watch later queue after this percent is watched.
+// Last minutes of the video are usually the ceremony which nobody watches till the end
+const FINISHED_PERCENT = 90
+
+// playWatchLater plays watch later queue from the oldest video.
+// Stops when video was closed before it's finished, so closing the player doesn't start the next one
+func playWatchLater(refresh bool) {
+	for {
+		entries, err := config.LoadWatchLater()
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if len(entries) == 0 {
+			fmt.Println("Watch later queue is empty")
+			return
+		}
+		entry := entries[0]
+		fmt.Printf("Playing %s, %d left in watch later queue\n", entry.URL, len(entries)-1)
+		playVideo(entry.URL, refresh)
+
+		finished, err := isFinished(entry.URL)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if !finished {
+			fmt.Printf("Video %s is not finished, it stays in watch later queue\n", entry.URL)
+			return
+		}
+		if err := removeFromWatchLater(entry.URL); err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
+
+func isFinished(videoUrl string) (bool, error) {
+	watchedFileName, err := getWatchedFileName(videoUrl)
+	if err != nil {
+		return false, err
+	}
+	watchedTime, err := ReadWatchedTime(watchedFileName)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if watchedTime.Duration > 0 {
+		return watchedTime.Percent >= FINISHED_PERCENT, nil
+	}
+	// Scripts passed with --saveWatchedTimeMpvScript may save only seconds, so take duration from the channel listing
+	duration := getListingDuration(videoUrl)
+	if duration == 0 {
+		if !warnedNoDuration {
+			fmt.Println("Warning: video duration is unknown, finished videos are removed from watch later queue only with the bundled mpv script")
+			warnedNoDuration = true
+		}
+		return false, nil
+	}
+	return watchedTime.Seconds*100 >= duration.Seconds()*FINISHED_PERCENT, nil
+}
+
+var warnedNoDuration bool
+
+// getListingDuration returns duration of the video from cached channel listing without calling yt-dlp, 0 when it's not there
+func getListingDuration(videoUrl string) time.Duration {
+	youtubeId, err := youtubeparser.GetYouTubeId(videoUrl)
+	if err != nil {
+		return 0
+	}
+	videos, err := youtubeparser.FetchWttVideos(&youtubeparser.Filters{DisableAllFilters: true, ShowWatched: true, Offline: true, Raw: true})
+	if err != nil {
+		return 0
+	}
+	for _, video := range videos {
+		if video.ID == youtubeId {
+			return video.Duration
+		}
+	}
+	return 0
+}
+
+// removeFromWatchLater reloads the queue since videos could be added while the previous one was playing
+func removeFromWatchLater(videoUrl string) error {
+	entries, err := config.LoadWatchLater()
+	if err != nil {
+		return err
+	}
+	entries = slices.DeleteFunc(entries, func(entry config.WatchLaterEntry) bool {
+		return entry.URL == videoUrl
+	})
+	return config.SaveWatchLater(entries)
+}
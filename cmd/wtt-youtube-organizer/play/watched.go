@@ -0,0 +1,43 @@
+package play
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// WatchedTime is the content of the watched file written by mpv script on exit.
+// Script writes it to temp file and renames, so readers never see partially written file.
+// Numbers are floats because mpv may format whole lua numbers as 120.000000
+type WatchedTime struct {
+	Seconds  float64 `json:"seconds"`
+	Duration float64 `json:"duration"`
+	Percent  float64 `json:"percent"`
+}
+
+// ReadWatchedTime parses watched file in json format.
+// Files saved by older versions of mpv script contain only the number of seconds
+func ReadWatchedTime(watchedFileName string) (*WatchedTime, error) {
+	data, err := os.ReadFile(watchedFileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error reading file %s: %v", watchedFileName, err)
+	}
+	content := strings.TrimSpace(string(data))
+	if strings.HasPrefix(content, "{") {
+		var watchedTime WatchedTime
+		if err := json.Unmarshal([]byte(content), &watchedTime); err != nil {
+			return nil, fmt.Errorf("error parsing watched time %s from %s: %v", content, watchedFileName, err)
+		}
+		return &watchedTime, nil
+	}
+	seconds, err := strconv.ParseUint(content, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing watched seconds %s from %s: %v", content, watchedFileName, err)
+	}
+	return &WatchedTime{Seconds: float64(seconds)}, nil
+}
@@ -0,0 +1,100 @@
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"wtt-youtube-organizer/utils"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const example = `
+		{cmd} serve
+		{cmd} serve --addr localhost:9000 --gender MS
+		curl 'http://localhost:8080/videos?tour=Champions&player=Wang'
+`
+
+var addr string
+
+func NewCommand(filters *youtubeparser.Filters) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "serve",
+		Short:        "Serves wtt videos over HTTP",
+		Long:         "Runs HTTP server with JSON API for web front-ends. Filters passed to serve are defaults for every request",
+		Example:      utils.FormatExample.Replace(example),
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(filters)
+		},
+	}
+	initCmd(cmd.Flags())
+	return cmd
+}
+
+func initCmd(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(&addr, "addr", "localhost:8080", "Address to listen on")
+}
+
+func serve(filters *youtubeparser.Filters) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/videos", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		handleVideos(w, r, filters)
+	})
+	// Write timeout is long enough for yt-dlp fetching the channel when cache is expired
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		WriteTimeout:      5 * time.Minute,
+	}
+	fmt.Printf("Listening on http://%s\n", addr)
+	return server.ListenAndServe()
+}
+
+// handleVideos returns videos in the same form as show --output json.
+// Query params tour, gender and filter override the corresponding flags, player keeps matches of the player only
+func handleVideos(w http.ResponseWriter, r *http.Request, filters *youtubeparser.Filters) {
+	requestFilters := *filters
+	// Gender default warning is meant for the console, not for every request
+	requestFilters.GenderNotSet = false
+	query := r.URL.Query()
+	if query.Has("tour") {
+		requestFilters.Tournament = query.Get("tour")
+	}
+	if query.Has("gender") {
+		requestFilters.Gender = query.Get("gender")
+	}
+	if query.Has("filter") {
+		requestFilters.Filter = query.Get("filter")
+	}
+	player := strings.ToLower(query.Get("player"))
+
+	videos, err := youtubeparser.FetchWttVideos(&requestFilters)
+	if err != nil {
+		log.Printf("Failed to fetch videos: %v", err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	rows := make([]youtubeparser.VideoRow, 0, len(videos))
+	for _, video := range videos {
+		if player != "" && !strings.Contains(strings.ToLower(video.Players), player) {
+			continue
+		}
+		rows = append(rows, youtubeparser.NewVideoRow(video, &requestFilters))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rows); err != nil {
+		log.Printf("Failed to write videos response: %v", err)
+	}
+}
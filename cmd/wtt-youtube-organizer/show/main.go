@@ -2,6 +2,10 @@ package show
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"wtt-youtube-organizer/formatter"
 	"wtt-youtube-organizer/utils"
 	youtubeparser "wtt-youtube-organizer/youtube_parser"
 
@@ -11,8 +15,19 @@ import (
 
 const example = `
 		{cmd} show
+		{cmd} show --groupBy tournament
+		{cmd} show --output json | jq '.[].url'
 `
 
+var groupByValues = []string{"tournament", "day", "round"}
+
+var timezone string
+var utc bool
+var iso bool
+var newWithin time.Duration
+var groupBy string
+var output string
+
 func NewCommand(filters *youtubeparser.Filters) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:          "show",
@@ -21,19 +36,84 @@ func NewCommand(filters *youtubeparser.Filters) *cobra.Command {
 		Example:      utils.FormatExample.Replace(example),
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
-		Run: func(cmd *cobra.Command, args []string) {
-			show(filters)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if groupBy != "" && !slices.Contains(groupByValues, groupBy) {
+				return fmt.Errorf("unknown --groupBy %s, expected one of %s", groupBy, strings.Join(groupByValues, ", "))
+			}
+			if !slices.Contains(outputValues, output) {
+				return fmt.Errorf("unknown --output %s, expected one of %s", output, strings.Join(outputValues, ", "))
+			}
+			if groupBy != "" && output != OUTPUT_TEXT {
+				return fmt.Errorf("--groupBy is supported only with --output %s", OUTPUT_TEXT)
+			}
+			dateFormatter, err := formatter.NewDateFormatter(timezone, utc, iso)
+			if err != nil {
+				return err
+			}
+			return show(filters, dateFormatter)
 		},
 	}
 	initCmd(cmd.Flags())
 	return cmd
 }
 
-func initCmd(_ *pflag.FlagSet) {
+func initCmd(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(&timezone, "timezone", "", "Timezone to show dates in, eg. Europe/Berlin. Local timezone by default")
+	flagSet.BoolVar(&utc, "utc", false, "Show dates in UTC")
+	flagSet.BoolVar(&iso, "iso", false, "Show dates as YYYY-MM-DD")
+	flagSet.DurationVar(&newWithin, "newWithin", 24*time.Hour, "Mark videos uploaded within this period with NEW badge. 0 disables the badge")
+	flagSet.StringVar(&groupBy, "groupBy", "", "Group videos under headers. One of tournament, day, round")
+	flagSet.StringVar(&output, "output", OUTPUT_TEXT, "Output format. One of text, json, csv, table")
+}
+
+func show(filters *youtubeparser.Filters, dateFormatter *formatter.DateFormatter) error {
+	videos := youtubeparser.FilterWttVideos(filters)
+	if output != OUTPUT_TEXT {
+		return printVideos(videos, filters, dateFormatter, output)
+	}
+	if groupBy == "" {
+		for _, video := range videos {
+			printVideo(video, filters, dateFormatter)
+		}
+		return nil
+	}
+	// Groups keep order of their first video, so the newest tournament is still the last one
+	var groups []string
+	groupVideos := make(map[string][]*youtubeparser.YoutubeVideo)
+	for _, video := range videos {
+		group := getGroup(video, filters, dateFormatter)
+		if _, ok := groupVideos[group]; !ok {
+			groups = append(groups, group)
+		}
+		groupVideos[group] = append(groupVideos[group], video)
+	}
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("== %s (%d) ==\n", group, len(groupVideos[group]))
+		for _, video := range groupVideos[group] {
+			printVideo(video, filters, dateFormatter)
+		}
+	}
+	return nil
+}
+
+func getGroup(video *youtubeparser.YoutubeVideo, filters *youtubeparser.Filters, dateFormatter *formatter.DateFormatter) string {
+	switch groupBy {
+	case "tournament":
+		return video.Tournament
+	case "day":
+		return dateFormatter.FormatUploadDate(video.UploadDate)
+	default:
+		return video.Gender + " " + video.DisplayRound(filters)
+	}
 }
 
-func show(filters *youtubeparser.Filters) {
-	for _, video := range youtubeparser.FilterWttVideos(filters) {
-		fmt.Printf("%s: %s - %s\n", video.UploadDate, video.Title, video.URL)
+func printVideo(video *youtubeparser.YoutubeVideo, filters *youtubeparser.Filters, dateFormatter *formatter.DateFormatter) {
+	badge := ""
+	if newWithin > 0 && dateFormatter.IsNew(video.UploadDate, video.Timestamp, newWithin) {
+		badge = "[NEW] "
 	}
+	fmt.Printf("%s%s: %s - %s\n", badge, dateFormatter.FormatUploadTime(video.UploadDate, video.Timestamp), video.DisplayTitle(filters), video.URL)
 }
@@ -0,0 +1,65 @@
+package show
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+	"wtt-youtube-organizer/formatter"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+)
+
+// Values of --output
+const (
+	OUTPUT_TEXT  = "text"
+	OUTPUT_JSON  = "json"
+	OUTPUT_CSV   = "csv"
+	OUTPUT_TABLE = "table"
+)
+
+var outputValues = []string{OUTPUT_TEXT, OUTPUT_JSON, OUTPUT_CSV, OUTPUT_TABLE}
+
+var csvHeader = []string{"id", "url", "title", "upload_date", "timestamp", "duration_seconds", "full_match", "players", "gender", "round", "tournament"}
+
+func csvRecord(r youtubeparser.VideoRow) []string {
+	return []string{r.ID, r.URL, r.Title, r.UploadDate, strconv.FormatInt(r.Timestamp, 10), strconv.FormatInt(r.DurationSeconds, 10),
+		strconv.FormatBool(r.FullMatch), r.Players, r.Gender, r.Round, r.Tournament}
+}
+
+// printVideos writes videos in one of machine readable or table formats to stdout
+func printVideos(videos []*youtubeparser.YoutubeVideo, filters *youtubeparser.Filters, dateFormatter *formatter.DateFormatter, output string) error {
+	rows := make([]youtubeparser.VideoRow, 0, len(videos))
+	for _, video := range videos {
+		rows = append(rows, youtubeparser.NewVideoRow(video, filters))
+	}
+	switch output {
+	case OUTPUT_JSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	case OUTPUT_CSV:
+		writer := csv.NewWriter(os.Stdout)
+		if err := writer.Write(csvHeader); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := writer.Write(csvRecord(row)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case OUTPUT_TABLE:
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "DATE\tTOURNAMENT\tGENDER\tROUND\tPLAYERS\tDURATION\tURL")
+		for _, row := range rows {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", dateFormatter.FormatUploadTime(row.UploadDate, row.Timestamp),
+				row.Tournament, row.Gender, row.Round, row.Players, time.Duration(row.DurationSeconds)*time.Second, row.URL)
+		}
+		return writer.Flush()
+	}
+	return fmt.Errorf("unknown output format %s", output)
+}
@@ -0,0 +1,108 @@
+package smoke
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/play"
+	"wtt-youtube-organizer/utils"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const example = `
+		{cmd} smoke
+		{cmd} smoke --videos 10
+`
+
+var videosCount int
+
+type stageResult struct {
+	name   string
+	err    error
+	detail string
+}
+
+func NewCommand(filters *youtubeparser.Filters) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "smoke",
+		Short:        "Checks the whole read and playback path without changing anything",
+		Long:         "Fetches a few videos, parses their titles, resolves stream urls of one video and checks mpv is available. Prints pass/fail for each stage",
+		Example:      utils.FormatExample.Replace(example),
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if filters.Offline {
+				return fmt.Errorf("smoke checks network access: %w", youtubeparser.ErrOffline)
+			}
+			return smoke()
+		},
+	}
+	initCmd(cmd.Flags())
+	return cmd
+}
+
+func initCmd(flagSet *pflag.FlagSet) {
+	flagSet.IntVar(&videosCount, "videos", 5, "Amount of latest channel entries to fetch")
+}
+
+// smoke never writes anything: no folders generated and no watched state touched
+func smoke() error {
+	var results []stageResult
+
+	videos, fetched, err := youtubeparser.FetchLatestVideos(videosCount)
+	results = append(results, stageResult{name: "fetch", err: err, detail: fmt.Sprintf("%d entries", fetched)})
+
+	var parseErr error
+	switch {
+	case err != nil:
+		parseErr = fmt.Errorf("skipped, nothing fetched")
+	case len(videos) == 0:
+		parseErr = fmt.Errorf("none of %d titles parsed", fetched)
+	}
+	results = append(results, stageResult{name: "parse", err: parseErr, detail: fmt.Sprintf("%d of %d titles parsed", len(videos), fetched)})
+
+	var resolveErr error
+	resolveDetail := ""
+	if len(videos) == 0 {
+		resolveErr = fmt.Errorf("skipped, no parsed videos")
+	} else {
+		videoLink, audioLink, err := play.GetVideoUrlsFromYtDlp(videos[0].URL)
+		resolveErr = err
+		if err == nil && videoLink == "" {
+			resolveErr = fmt.Errorf("yt-dlp returned no links for %s", videos[0].URL)
+		}
+		resolveDetail = fmt.Sprintf("%s video:%t audio:%t", videos[0].URL, videoLink != "", audioLink != "")
+	}
+	results = append(results, stageResult{name: "resolve", err: resolveErr, detail: resolveDetail})
+
+	mpvVersion, err := checkMpv()
+	results = append(results, stageResult{name: "mpv", err: err, detail: mpvVersion})
+
+	failed := 0
+	for _, result := range results {
+		if result.err != nil {
+			failed++
+			fmt.Printf("FAIL %-8s %v\n", result.name, result.err)
+			continue
+		}
+		fmt.Printf("PASS %-8s %s\n", result.name, result.detail)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d smoke stages failed", failed, len(results))
+	}
+	return nil
+}
+
+func checkMpv() (string, error) {
+	if _, err := exec.LookPath("mpv"); err != nil {
+		return "", fmt.Errorf("mpv not found in PATH: %v", err)
+	}
+	out, err := exec.Command("mpv", "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run mpv --version: %v", err)
+	}
+	return strings.SplitN(string(out), "\n", 2)[0], nil
+}
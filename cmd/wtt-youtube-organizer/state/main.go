@@ -0,0 +1,90 @@
+package state
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/pipeline"
+	"wtt-youtube-organizer/cmd/wtt-youtube-organizer/play"
+	"wtt-youtube-organizer/config"
+	"wtt-youtube-organizer/utils"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+
+	"github.com/spf13/cobra"
+)
+
+const example = `
+		{cmd} state export wtt-state.tar.gz
+		{cmd} state import wtt-state.tar.gz
+`
+
+// Runtime files which make no sense on another machine. Stream links are bound to the ip they were resolved from
+var transientPaths = []string{pipeline.LOCK_FILE_NAME, play.PLAYING_DIR, filepath.Join(config.CacheDirName, play.STREAM_URLS_DIR)}
+
+func NewCommand(_ *youtubeparser.Filters) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "state",
+		Short:   "Exports and imports application state to move it to another machine",
+		Long:    "Exports and imports everything stored in the config folder, eg. watched time of videos",
+		Example: utils.FormatExample.Replace(example),
+		Args:    cobra.MinimumNArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:          "export FILE",
+		Short:        "Packs application state into tar.gz archive",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exportState(args[0])
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:          "import FILE",
+		Short:        "Restores application state from tar.gz archive created by state export",
+		Long:         "Restores application state from tar.gz archive created by state export. Files from archive overwrite existing ones",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return importState(args[0])
+		},
+	})
+	return cmd
+}
+
+func exportState(archivePath string) error {
+	configDir := utils.CreateFolderIfNoExist(config.GetProjectConfigDir())
+	absArchivePath, err := filepath.Abs(archivePath)
+	if err != nil {
+		return err
+	}
+	files, err := utils.TarGzFolder(configDir, archivePath, func(relPath string) bool {
+		// Archive itself could be created inside config folder
+		if filepath.Join(configDir, relPath) == absArchivePath {
+			return true
+		}
+		for _, transient := range transientPaths {
+			if relPath == transient || strings.HasPrefix(relPath, transient+string(filepath.Separator)) {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d files from %s to %s\n", files, configDir, archivePath)
+	return nil
+}
+
+func importState(archivePath string) error {
+	configDir := utils.CreateFolderIfNoExist(config.GetProjectConfigDir())
+	files, err := utils.UntarGz(archivePath, configDir)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d files from %s to %s\n", files, archivePath, configDir)
+	return nil
+}
@@ -0,0 +1,66 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"wtt-youtube-organizer/utils"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const example = `
+		{cmd} version
+		{cmd} version --verbose
+`
+
+var verbose bool
+
+// External tools wtt-youtube-organizer depends on with args printing their version
+var tools = [][]string{
+	{"yt-dlp", "--version"},
+	{"mpv", "--version"},
+}
+
+func NewCommand(_ *youtubeparser.Filters) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "version",
+		Short:        "Prints version",
+		Long:         "Prints version. With --verbose also prints build info and versions of yt-dlp and mpv to attach to bug reports",
+		Example:      utils.FormatExample.Replace(example),
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			printVersion()
+		},
+	}
+	initCmd(cmd.Flags())
+	return cmd
+}
+
+func initCmd(flagSet *pflag.FlagSet) {
+	flagSet.BoolVar(&verbose, "verbose", false, "Print build info and versions of external tools")
+}
+
+func printVersion() {
+	fmt.Printf("%s %s\n", utils.MainCommand, utils.BuildCommit())
+	if !verbose {
+		return
+	}
+	for _, setting := range utils.BuildSettings() {
+		fmt.Printf("%s: %s\n", setting.Key, setting.Value)
+	}
+	for _, tool := range tools {
+		fmt.Printf("%s: %s\n", tool[0], getToolVersion(tool[0], tool[1:]...))
+	}
+}
+
+func getToolVersion(command string, args ...string) string {
+	out, err := exec.Command(command, args...).Output()
+	if err != nil {
+		return fmt.Sprintf("not available (%v)", err)
+	}
+	return strings.SplitN(strings.TrimSpace(string(out)), "\n", 2)[0]
+}
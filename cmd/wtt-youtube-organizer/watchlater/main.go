@@ -0,0 +1,123 @@
+package watchlater
+
+import (
+	"fmt"
+	"slices"
+	"time"
+	"wtt-youtube-organizer/config"
+	"wtt-youtube-organizer/utils"
+	youtubeparser "wtt-youtube-organizer/youtube_parser"
+
+	"github.com/spf13/cobra"
+)
+
+const example = `
+		{cmd} watchlater add "https://www.youtube.com/watch?v=lNOR7_52siI"
+		{cmd} watchlater list
+		{cmd} play --watchLater
+`
+
+func NewCommand(_ *youtubeparser.Filters) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "watchlater",
+		Short:   "Manages personal watch later queue",
+		Long:    "Manages personal watch later queue played with play --watchLater",
+		Example: utils.FormatExample.Replace(example),
+		Args:    cobra.MinimumNArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:          "add URL...",
+		Short:        "Adds videos to the end of watch later queue",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return add(args)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:          "list",
+		Short:        "Lists watch later queue",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return list()
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:          "remove URL...",
+		Short:        "Removes videos from watch later queue",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return remove(args)
+		},
+	})
+	return cmd
+}
+
+func add(videoUrls []string) error {
+	entries, err := config.LoadWatchLater()
+	if err != nil {
+		return err
+	}
+	for _, videoUrl := range videoUrls {
+		youtubeId, err := youtubeparser.GetYouTubeId(videoUrl)
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %v", videoUrl, err)
+		}
+		if indexOf(entries, youtubeId) != -1 {
+			fmt.Printf("%s is already in watch later queue\n", videoUrl)
+			continue
+		}
+		entries = append(entries, config.WatchLaterEntry{URL: videoUrl, Added: time.Now()})
+		fmt.Printf("Added %s\n", videoUrl)
+	}
+	return config.SaveWatchLater(entries)
+}
+
+func list() error {
+	entries, err := config.LoadWatchLater()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("Watch later queue is empty")
+		return nil
+	}
+	for i, entry := range entries {
+		fmt.Printf("%d. %s added %s\n", i+1, entry.URL, entry.Added.Format(time.DateTime))
+	}
+	return nil
+}
+
+func remove(videoUrls []string) error {
+	entries, err := config.LoadWatchLater()
+	if err != nil {
+		return err
+	}
+	for _, videoUrl := range videoUrls {
+		youtubeId, err := youtubeparser.GetYouTubeId(videoUrl)
+		if err != nil {
+			return fmt.Errorf("failed to remove %s: %v", videoUrl, err)
+		}
+		ind := indexOf(entries, youtubeId)
+		if ind == -1 {
+			fmt.Printf("%s is not in watch later queue\n", videoUrl)
+			continue
+		}
+		entries = slices.Delete(entries, ind, ind+1)
+		fmt.Printf("Removed %s\n", videoUrl)
+	}
+	return config.SaveWatchLater(entries)
+}
+
+// indexOf compares video ids, so the same video added with different url forms is found
+func indexOf(entries []config.WatchLaterEntry, youtubeId string) int {
+	return slices.IndexFunc(entries, func(entry config.WatchLaterEntry) bool {
+		entryId, err := youtubeparser.GetYouTubeId(entry.URL)
+		return err == nil && entryId == youtubeId
+	})
+}
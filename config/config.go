@@ -4,10 +4,14 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
 )
 
 const appName = "wtt-youtube-organizer"
 
+// CacheDirName is the name of cache folder inside of the project config folder
+const CacheDirName = "cache"
+
 func getConfigDir() string {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
@@ -19,3 +23,28 @@ func getConfigDir() string {
 func GetProjectConfigDir() string {
 	return filepath.Join(getConfigDir(), appName)
 }
+
+// GetMpvScriptsDir returns folder mpv loads scripts from automatically.
+// mpv reads ~/.config/mpv on macOS too, so os.UserConfigDir is used only on windows
+func GetMpvScriptsDir() string {
+	return filepath.Join(getMpvConfigDir(), "scripts")
+}
+
+func getMpvConfigDir() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(getConfigDir(), "mpv")
+	}
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "mpv")
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		log.Fatalln("Failed to get home folder")
+	}
+	return filepath.Join(homeDir, ".config", "mpv")
+}
+
+// GetCacheDir returns folder for data which could be safely removed and fetched again
+func GetCacheDir() string {
+	return filepath.Join(GetProjectConfigDir(), CacheDirName)
+}
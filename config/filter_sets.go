@@ -0,0 +1,29 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"wtt-youtube-organizer/utils"
+)
+
+const filterSetsFileName = "filter_sets.json"
+
+// FilterSets maps set name to the filter flags and their values, eg. weekday-singles -> {gender: MS, full: true}
+type FilterSets map[string]map[string]string
+
+func getFilterSetsPath() string {
+	return filepath.Join(GetProjectConfigDir(), filterSetsFileName)
+}
+
+// LoadFilterSets returns empty sets when nothing was saved yet
+func LoadFilterSets() (FilterSets, error) {
+	filterSets := FilterSets{}
+	if err := utils.LoadJSON(getFilterSetsPath(), &filterSets); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return filterSets, nil
+}
+
+func SaveFilterSets(filterSets FilterSets) error {
+	return utils.SaveJSON(getFilterSetsPath(), filterSets)
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"wtt-youtube-organizer/utils"
+)
+
+const installedMpvScriptFileName = "installed_mpv_script.json"
+
+// InstalledMpvScript remembers where install mpv-script put the script, since it could be a custom mpv scripts folder
+type InstalledMpvScript struct {
+	Path string `json:"path"`
+}
+
+func getInstalledMpvScriptPath() string {
+	return filepath.Join(GetProjectConfigDir(), installedMpvScriptFileName)
+}
+
+// LoadInstalledMpvScript returns path of the script installed by install mpv-script, empty when it was never run
+func LoadInstalledMpvScript() (string, error) {
+	var installed InstalledMpvScript
+	if err := utils.LoadJSON(getInstalledMpvScriptPath(), &installed); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	return installed.Path, nil
+}
+
+func SaveInstalledMpvScript(path string) error {
+	return utils.SaveJSON(getInstalledMpvScriptPath(), InstalledMpvScript{Path: path})
+}
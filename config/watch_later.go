@@ -0,0 +1,32 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+	"wtt-youtube-organizer/utils"
+)
+
+const watchLaterFileName = "watch_later.json"
+
+type WatchLaterEntry struct {
+	URL   string    `json:"url"`
+	Added time.Time `json:"added"`
+}
+
+func getWatchLaterPath() string {
+	return filepath.Join(GetProjectConfigDir(), watchLaterFileName)
+}
+
+// LoadWatchLater returns personal to-watch queue in the order videos were added
+func LoadWatchLater() ([]WatchLaterEntry, error) {
+	var entries []WatchLaterEntry
+	if err := utils.LoadJSON(getWatchLaterPath(), &entries); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func SaveWatchLater(entries []WatchLaterEntry) error {
+	return utils.SaveJSON(getWatchLaterPath(), entries)
+}
@@ -5,22 +5,43 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
 	"wtt-youtube-organizer/utils"
 	youtubeparser "wtt-youtube-organizer/youtube_parser"
 )
 
-const scriptTemplate = `#!/bin/sh
-{{.EXECUTABLE}} play --videoUrl "{{.VIDEO_URL}}" {{.LUA_SCRIPT_ARG}}`
-
 type ReplaceTemplate struct {
 	VIDEO_URL      string
 	EXECUTABLE     string
 	LUA_SCRIPT_ARG string
 }
 
-func CreateFolders(videos []*youtubeparser.YoutubeVideo, saveWatchedTimeMpvScript string) error {
+// Launcher orders selectable with --sortBy
+const (
+	SORT_NONE   = ""
+	SORT_UPLOAD = "upload"
+	SORT_ROUND  = "round"
+)
+
+var SortValues = []string{SORT_UPLOAD, SORT_ROUND}
+
+// CreateFolders generates launchers for videos. With sortBy upload launchers in each folder
+// get 01_, 02_ prefixes so file managers list them in upload order instead of by player name.
+// With sortBy round round folders get the prefix of their stage, eg. 08_QF, so they are listed in bracket order.
+// Empty launcherType picks launcher native to the current OS
+func CreateFolders(videos []*youtubeparser.YoutubeVideo, filters *youtubeparser.Filters, saveWatchedTimeMpvScript string, sortBy string, launcherType string) error {
+	if sortBy != SORT_NONE && !slices.Contains(SortValues, sortBy) {
+		return fmt.Errorf("unknown sort %s, expected one of %s", sortBy, strings.Join(SortValues, ", "))
+	}
+	if launcherType == "" {
+		launcherType = DefaultLauncherType()
+	}
+	launcher, ok := launchers[launcherType]
+	if !ok {
+		return fmt.Errorf("unknown launcher type %s, expected one of %s", launcherType, strings.Join(LauncherTypes, ", "))
+	}
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		log.Fatalf("Failed to get home directory: %v", err)
@@ -29,10 +50,20 @@ func CreateFolders(videos []*youtubeparser.YoutubeVideo, saveWatchedTimeMpvScrip
 	utils.CreateFolderIfNoExist(rootFolder)
 
 	emptyFolder(rootFolder)
+	launchersInFolder := make(map[string]int)
 	for _, video := range videos {
-		tourPath := utils.CreateFolderIfNoExist(filepath.Join(rootFolder, video.Tournament))
-		roundPath := utils.CreateFolderIfNoExist(filepath.Join(tourPath, video.Round))
-		err := createShLauncher(roundPath, saveWatchedTimeMpvScript, video)
+		tourPath := utils.CreateFolderIfNoExist(filepath.Join(rootFolder, sanitizeFileName(video.Tournament)))
+		round := video.DisplayRound(filters)
+		if sortBy == SORT_ROUND {
+			round = fmt.Sprintf("%02d_%s", youtubeparser.RoundIndex(round)+1, round)
+		}
+		roundPath := utils.CreateFolderIfNoExist(filepath.Join(tourPath, sanitizeFileName(round)))
+		prefix := ""
+		if sortBy == SORT_UPLOAD {
+			launchersInFolder[roundPath]++
+			prefix = fmt.Sprintf("%02d_", launchersInFolder[roundPath])
+		}
+		err := createLauncher(launcher, roundPath, saveWatchedTimeMpvScript, prefix, video)
 		if err != nil {
 			return err
 		}
@@ -40,14 +71,15 @@ func CreateFolders(videos []*youtubeparser.YoutubeVideo, saveWatchedTimeMpvScrip
 	return nil
 }
 
-func createShLauncher(folder string, saveWatchedTimeMpvScript string, video *youtubeparser.YoutubeVideo) error {
-	filename := video.Players + ".sh"
+func createLauncher(launcher launcher, folder string, saveWatchedTimeMpvScript string, prefix string, video *youtubeparser.YoutubeVideo) error {
+	filename := video.Players + launcher.Extension
 	if video.FullMatch {
 		filename = "FULL_" + filename
 	}
+	filename = prefix + filename
 	filename = strings.ReplaceAll(filename, "/", " and ")
-	filename = filepath.Join(folder, filename)
-	tmpl, err := template.New("script").Parse(scriptTemplate)
+	filename = filepath.Join(folder, sanitizeFileName(filename))
+	tmpl, err := template.New("script").Parse(launcher.Template)
 	if err != nil {
 		return fmt.Errorf("error parsing template: %v", err)
 	}
@@ -59,18 +91,21 @@ func createShLauncher(folder string, saveWatchedTimeMpvScript string, video *you
 
 	exePath, err := getExecutablePath()
 	if err != nil {
-		log.Fatalf("Failed to create sh launcher : %v", err)
+		log.Fatalf("Failed to create launcher : %v", err)
 	}
 	var saveWatchedTimeArg string
 	if saveWatchedTimeMpvScript != "" {
-		saveWatchedTimeArg = fmt.Sprintf("--saveWatchedTimeMpvScript \"%s\"", saveWatchedTimeMpvScript)
+		saveWatchedTimeArg = "--saveWatchedTimeMpvScript " + launcher.Quote(saveWatchedTimeMpvScript)
 	}
 	// Execute the template with the URL data
-	err = tmpl.Execute(file, ReplaceTemplate{VIDEO_URL: video.URL, EXECUTABLE: exePath, LUA_SCRIPT_ARG: saveWatchedTimeArg})
+	err = tmpl.Execute(file, ReplaceTemplate{VIDEO_URL: launcher.Quote(video.URL), EXECUTABLE: launcher.QuoteExecutable(exePath), LUA_SCRIPT_ARG: saveWatchedTimeArg})
 	if err != nil {
 		return fmt.Errorf("error executing template: %v", err)
 	}
 
+	if !launcher.Executable {
+		return nil
+	}
 	// Make the script executable
 	err = os.Chmod(filename, 0755)
 	if err != nil {
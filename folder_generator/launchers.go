@@ -0,0 +1,90 @@
+package foldergenerator
+
+import (
+	"runtime"
+	"strings"
+)
+
+// Launcher types selectable with --launcherType
+const (
+	LAUNCHER_SH  = "sh"
+	LAUNCHER_BAT = "bat"
+	LAUNCHER_PS1 = "ps1"
+)
+
+var LauncherTypes = []string{LAUNCHER_SH, LAUNCHER_BAT, LAUNCHER_PS1}
+
+// launcher describes script which starts play for a video.
+// Template gets values already quoted for the script language
+type launcher struct {
+	Extension       string
+	Template        string
+	Quote           func(string) string
+	QuoteExecutable func(string) string
+	// Executable launchers need exec permission to start from file manager
+	Executable bool
+}
+
+var launchers = map[string]launcher{
+	LAUNCHER_SH: {
+		Extension: ".sh",
+		Template: `#!/bin/sh
+{{.EXECUTABLE}} play --videoUrl {{.VIDEO_URL}} {{.LUA_SCRIPT_ARG}}`,
+		Quote:           quoteSh,
+		QuoteExecutable: quoteSh,
+		Executable:      true,
+	},
+	// cmd.exe expands %VAR% even inside quotes, so percents of url encoded links are doubled
+	LAUNCHER_BAT: {
+		Extension:       ".bat",
+		Template:        "@echo off\r\n{{.EXECUTABLE}} play --videoUrl {{.VIDEO_URL}} {{.LUA_SCRIPT_ARG}}\r\n",
+		Quote:           quoteBat,
+		QuoteExecutable: quoteBat,
+	},
+	// Single quoted strings are literal in powershell, & runs the quoted executable path
+	LAUNCHER_PS1: {
+		Extension:       ".ps1",
+		Template:        "& {{.EXECUTABLE}} play --videoUrl {{.VIDEO_URL}} {{.LUA_SCRIPT_ARG}}\r\n",
+		Quote:           quotePowershell,
+		QuoteExecutable: quotePowershell,
+	},
+}
+
+// DefaultLauncherType returns launcher type native to the current OS
+func DefaultLauncherType() string {
+	if runtime.GOOS == "windows" {
+		return LAUNCHER_BAT
+	}
+	return LAUNCHER_SH
+}
+
+// quoteSh single quotes value, so spaces and $ in install paths are kept as is
+func quoteSh(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// cmd.exe has no escape for quotes and line breaks inside quoted string. They can't appear in windows paths,
+// in urls they are percent encoded, which is the same url
+var batReplacer = strings.NewReplacer("%", "%%", `"`, "%%22", "\r", "%%0D", "\n", "%%0A")
+
+func quoteBat(value string) string {
+	return `"` + batReplacer.Replace(value) + `"`
+}
+
+// powershell treats typographic single quotes the same as ' and all of them are escaped by doubling
+var powershellReplacer = strings.NewReplacer("'", "''", "\u2018", "\u2018\u2018", "\u2019", "\u2019\u2019", "\u201A", "\u201A\u201A", "\u201B", "\u201B\u201B")
+
+func quotePowershell(value string) string {
+	return "'" + powershellReplacer.Replace(value) + "'"
+}
+
+// Characters windows doesn't allow in file names
+var windowsFileNameReplacer = strings.NewReplacer("<", "_", ">", "_", ":", "_", `"`, "_", `\`, "_", "|", "_", "?", "_", "*", "_")
+
+// sanitizeFileName replaces characters which are invalid in file names on the current OS
+func sanitizeFileName(name string) string {
+	if runtime.GOOS != "windows" {
+		return name
+	}
+	return windowsFileNameReplacer.Replace(name)
+}
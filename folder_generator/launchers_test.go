@@ -0,0 +1,108 @@
+package foldergenerator
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+var quoteTestValues = []string{
+	"/usr/local/bin/wtt-youtube-organizer",
+	"/home/user/my apps/wtt",
+	`C:\Program Files\wtt\wtt.exe`,
+	"/tmp/it's/wtt",
+	`/tmp/say "hi"/wtt`,
+	"/tmp/100%/wtt",
+	"/tmp/$HOME/$(reboot)/wtt",
+	"/tmp/`reboot`/wtt",
+	"/tmp/a&b/wtt & calc",
+	"/tmp/a^b/wtt",
+	"/tmp/'; rm -rf ~; echo '/wtt",
+	"https://www.youtube.com/watch?v=lNOR7_52siI&t=10%20s",
+	"https://www.youtube.com/watch?v=a\"&calc&\"",
+	"https://www.youtube.com/watch?v=a%PATH%b",
+	"/tmp/line\nbreak/wtt",
+	"/tmp/\u2018smart\u2019 quotes/wtt",
+}
+
+// Runs printf through real sh, so any breakout out of quotes changes printed value
+func TestQuoteSh(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	for _, value := range quoteTestValues {
+		out, err := exec.Command("sh", "-c", "printf '%s' "+quoteSh(value)).Output()
+		if err != nil {
+			t.Errorf("sh failed for %q quoted as %s: %v", value, quoteSh(value), err)
+			continue
+		}
+		if string(out) != value {
+			t.Errorf("quoteSh(%q) = %s, sh reads it as %q", value, quoteSh(value), out)
+		}
+	}
+}
+
+// parseBat reads quoted argument the way cmd.exe does in a batch file and fails on anything
+// which would end the quoted string early or expand variables
+func parseBat(t *testing.T, quoted string) string {
+	t.Helper()
+	if len(quoted) < 2 || quoted[0] != '"' || quoted[len(quoted)-1] != '"' {
+		t.Fatalf("%s is not quoted", quoted)
+	}
+	inner := quoted[1 : len(quoted)-1]
+	if strings.ContainsAny(inner, "\"\r\n") {
+		t.Fatalf("%s breaks out of quotes", quoted)
+	}
+	var value strings.Builder
+	for i := 0; i < len(inner); i++ {
+		if inner[i] == '%' {
+			if i+1 >= len(inner) || inner[i+1] != '%' {
+				t.Fatalf("%s has unescaped %% which cmd.exe expands", quoted)
+			}
+			i++
+		}
+		value.WriteByte(inner[i])
+	}
+	return value.String()
+}
+
+func TestQuoteBat(t *testing.T) {
+	// Quotes and line breaks are percent encoded, everything else including & and ^ is literal inside quotes
+	encode := strings.NewReplacer(`"`, "%22", "\r", "%0D", "\n", "%0A")
+	for _, value := range quoteTestValues {
+		if got := parseBat(t, quoteBat(value)); got != encode.Replace(value) {
+			t.Errorf("quoteBat(%q) = %s, cmd.exe reads it as %q", value, quoteBat(value), got)
+		}
+	}
+}
+
+const powershellQuotes = "'\u2018\u2019\u201A\u201B"
+
+// parsePowershell reads single quoted string the way powershell does, where doubled quote is a literal quote
+func parsePowershell(t *testing.T, quoted string) string {
+	t.Helper()
+	runes := []rune(quoted)
+	if len(runes) < 2 || runes[0] != '\'' || runes[len(runes)-1] != '\'' {
+		t.Fatalf("%s is not quoted", quoted)
+	}
+	inner := runes[1 : len(runes)-1]
+	var value strings.Builder
+	for i := 0; i < len(inner); i++ {
+		if strings.ContainsRune(powershellQuotes, inner[i]) {
+			if i+1 >= len(inner) || !strings.ContainsRune(powershellQuotes, inner[i+1]) {
+				t.Fatalf("%s breaks out of quotes", quoted)
+			}
+			i++
+		}
+		value.WriteRune(inner[i])
+	}
+	return value.String()
+}
+
+func TestQuotePowershell(t *testing.T) {
+	for _, value := range quoteTestValues {
+		if got := parsePowershell(t, quotePowershell(value)); got != value {
+			t.Errorf("quotePowershell(%q) = %s, powershell reads it as %q", value, quotePowershell(value), got)
+		}
+	}
+}
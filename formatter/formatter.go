@@ -0,0 +1,113 @@
+package formatter
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Format of upload_date field returned by yt-dlp
+const UploadDateLayout = "20060102"
+
+type DateFormatter struct {
+	Location *time.Location
+	ISO      bool
+	Now      func() time.Time
+}
+
+// NewDateFormatter creates formatter for the given IANA timezone, eg. Europe/Berlin.
+// Empty timezone means local one. utc overrides the timezone and iso switches to YYYY-MM-DD dates without relative age
+func NewDateFormatter(timezone string, utc bool, iso bool) (*DateFormatter, error) {
+	location := time.Local
+	if timezone != "" {
+		var err error
+		location, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown timezone %s: %v", timezone, err)
+		}
+	}
+	if utc {
+		location = time.UTC
+	}
+	return &DateFormatter{Location: location, ISO: iso, Now: time.Now}, nil
+}
+
+// ParseUploadDate parses yt-dlp upload date as midnight in formatter timezone
+func (f *DateFormatter) ParseUploadDate(uploadDate string) (time.Time, error) {
+	date, err := time.ParseInLocation(UploadDateLayout, uploadDate, f.Location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error parsing upload date %s: %v", uploadDate, err)
+	}
+	return date, nil
+}
+
+// UploadTime returns upload time from the timestamp when it's known, otherwise midnight of the upload date
+func (f *DateFormatter) UploadTime(uploadDate string, timestamp int64) (time.Time, error) {
+	if timestamp > 0 {
+		return time.Unix(timestamp, 0).In(f.Location), nil
+	}
+	return f.ParseUploadDate(uploadDate)
+}
+
+// IsNew reports whether video was uploaded within the given period
+func (f *DateFormatter) IsNew(uploadDate string, timestamp int64, within time.Duration) bool {
+	uploadTime, err := f.UploadTime(uploadDate, timestamp)
+	if err != nil {
+		return false
+	}
+	return f.Now().Sub(uploadTime) <= within
+}
+
+// FormatUploadTime is FormatUploadDate which shows hours for videos uploaded during last day when timestamp is known
+func (f *DateFormatter) FormatUploadTime(uploadDate string, timestamp int64) string {
+	if timestamp <= 0 || f.ISO {
+		return f.FormatUploadDate(uploadDate)
+	}
+	uploadTime, _ := f.UploadTime(uploadDate, timestamp)
+	age := f.Now().Sub(uploadTime)
+	if age < 0 || age >= 24*time.Hour {
+		return f.FormatUploadDate(uploadDate)
+	}
+	relative := "less than an hour ago"
+	if hours := int(age.Hours()); hours == 1 {
+		relative = "1 hour ago"
+	} else if hours > 1 {
+		relative = fmt.Sprintf("%d hours ago", hours)
+	}
+	return fmt.Sprintf("%s (%s)", uploadTime.Format("Mon, 02 Jan"), relative)
+}
+
+// FormatUploadDate renders yt-dlp upload date like "Sat, 14 Jun (2 days ago)" or "2024-06-14" in iso mode.
+// Unparseable dates are returned as is
+func (f *DateFormatter) FormatUploadDate(uploadDate string) string {
+	date, err := f.ParseUploadDate(uploadDate)
+	if err != nil {
+		return uploadDate
+	}
+	if f.ISO {
+		return date.Format(time.DateOnly)
+	}
+	layout := "Mon, 02 Jan"
+	if date.Year() != f.Now().In(f.Location).Year() {
+		layout = "Mon, 02 Jan 2006"
+	}
+	return fmt.Sprintf("%s (%s)", date.Format(layout), f.RelativeDays(date))
+}
+
+// RelativeDays describes how many calendar days ago the date was, eg. today, yesterday, 5 days ago
+func (f *DateFormatter) RelativeDays(date time.Time) string {
+	now := f.Now().In(f.Location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, f.Location)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, f.Location)
+	// Rounding keeps days correct when range crosses daylight saving change
+	days := int(math.Round(today.Sub(day).Hours() / 24))
+	switch {
+	case days < 0:
+		return "in the future"
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "yesterday"
+	}
+	return fmt.Sprintf("%d days ago", days)
+}
@@ -0,0 +1,147 @@
+package formatter
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestFormatter(t *testing.T, timezone string, utc bool, iso bool, now time.Time) *DateFormatter {
+	t.Helper()
+	f, err := NewDateFormatter(timezone, utc, iso)
+	if err != nil {
+		t.Fatalf("NewDateFormatter(%q, %v, %v) returned error: %v", timezone, utc, iso, err)
+	}
+	f.Now = func() time.Time { return now }
+	return f
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load timezone %s: %v", name, err)
+	}
+	return location
+}
+
+func TestNewDateFormatter(t *testing.T) {
+	f := newTestFormatter(t, "Europe/Berlin", true, false, time.Now())
+	if f.Location != time.UTC {
+		t.Errorf("--utc with --timezone Europe/Berlin uses %v, want UTC", f.Location)
+	}
+	if _, err := NewDateFormatter("Mars/Olympus", false, false); err == nil {
+		t.Error("NewDateFormatter with unknown timezone returned no error")
+	}
+}
+
+func TestRelativeDays(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	newYork := mustLoadLocation(t, "America/New_York")
+	// 00:30 in Berlin is still the previous evening in New York
+	afterMidnight := time.Date(2024, 6, 14, 0, 30, 0, 0, berlin)
+	// Berlin switched to summer time at 2024-03-31 02:00, so that day is 23 hours long
+	afterDstChange := time.Date(2024, 3, 31, 12, 0, 0, 0, berlin)
+	tests := []struct {
+		timezone string
+		now      time.Time
+		date     time.Time
+		want     string
+	}{
+		{timezone: "Europe/Berlin", now: afterMidnight, date: time.Date(2024, 6, 14, 0, 0, 0, 0, berlin), want: "today"},
+		{timezone: "Europe/Berlin", now: afterMidnight, date: time.Date(2024, 6, 13, 0, 0, 0, 0, berlin), want: "yesterday"},
+		{timezone: "Europe/Berlin", now: afterMidnight, date: time.Date(2024, 6, 9, 0, 0, 0, 0, berlin), want: "5 days ago"},
+		{timezone: "Europe/Berlin", now: afterMidnight, date: time.Date(2024, 6, 15, 0, 0, 0, 0, berlin), want: "in the future"},
+		{timezone: "America/New_York", now: afterMidnight, date: time.Date(2024, 6, 13, 0, 0, 0, 0, newYork), want: "today"},
+		{timezone: "America/New_York", now: afterMidnight, date: time.Date(2024, 6, 12, 0, 0, 0, 0, newYork), want: "yesterday"},
+		{timezone: "America/New_York", now: afterMidnight, date: time.Date(2024, 6, 14, 0, 0, 0, 0, newYork), want: "in the future"},
+		{timezone: "Europe/Berlin", now: afterDstChange, date: time.Date(2024, 3, 30, 0, 0, 0, 0, berlin), want: "yesterday"},
+		{timezone: "Europe/Berlin", now: afterDstChange, date: time.Date(2024, 3, 20, 0, 0, 0, 0, berlin), want: "11 days ago"},
+	}
+	for _, test := range tests {
+		f := newTestFormatter(t, test.timezone, false, false, test.now)
+		if got := f.RelativeDays(test.date); got != test.want {
+			t.Errorf("RelativeDays(%s) in %s at %v = %q, want %q", test.date.Format(time.DateOnly), test.timezone, test.now, got, test.want)
+		}
+	}
+}
+
+func TestFormatUploadDate(t *testing.T) {
+	now := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		iso        bool
+		uploadDate string
+		want       string
+	}{
+		{uploadDate: "20250102", want: "Thu, 02 Jan (today)"},
+		{uploadDate: "20241230", want: "Mon, 30 Dec 2024 (3 days ago)"},
+		{uploadDate: "unknown", want: "unknown"},
+		{iso: true, uploadDate: "20241230", want: "2024-12-30"},
+		{iso: true, uploadDate: "unknown", want: "unknown"},
+	}
+	for _, test := range tests {
+		f := newTestFormatter(t, "", true, test.iso, now)
+		if got := f.FormatUploadDate(test.uploadDate); got != test.want {
+			t.Errorf("FormatUploadDate(%q) with iso=%v = %q, want %q", test.uploadDate, test.iso, got, test.want)
+		}
+	}
+}
+
+func TestFormatUploadTime(t *testing.T) {
+	// Uploaded 15 minutes before midnight UTC, which is already the next day in Berlin
+	uploaded := time.Date(2024, 6, 13, 23, 45, 0, 0, time.UTC)
+	timestamp := uploaded.Unix()
+	tests := []struct {
+		timezone   string
+		utc        bool
+		iso        bool
+		now        time.Time
+		uploadDate string
+		timestamp  int64
+		want       string
+	}{
+		{utc: true, now: uploaded.Add(45 * time.Minute), uploadDate: "20240613", timestamp: timestamp, want: "Thu, 13 Jun (less than an hour ago)"},
+		{timezone: "Europe/Berlin", now: uploaded.Add(45 * time.Minute), uploadDate: "20240613", timestamp: timestamp, want: "Fri, 14 Jun (less than an hour ago)"},
+		{timezone: "Europe/Berlin", utc: true, now: uploaded.Add(45 * time.Minute), uploadDate: "20240613", timestamp: timestamp, want: "Thu, 13 Jun (less than an hour ago)"},
+		{timezone: "America/New_York", now: uploaded.Add(90 * time.Minute), uploadDate: "20240613", timestamp: timestamp, want: "Thu, 13 Jun (1 hour ago)"},
+		{utc: true, now: uploaded.Add(5 * time.Hour), uploadDate: "20240613", timestamp: timestamp, want: "Thu, 13 Jun (5 hours ago)"},
+		// Older than a day or without timestamp falls back to the upload date
+		{utc: true, now: uploaded.Add(25 * time.Hour), uploadDate: "20240613", timestamp: timestamp, want: "Thu, 13 Jun (2 days ago)"},
+		{utc: true, now: uploaded.Add(45 * time.Minute), uploadDate: "20240613", want: "Thu, 13 Jun (yesterday)"},
+		{utc: true, now: uploaded.Add(-time.Hour), uploadDate: "20240613", timestamp: timestamp, want: "Thu, 13 Jun (today)"},
+		{utc: true, iso: true, now: uploaded.Add(45 * time.Minute), uploadDate: "20240613", timestamp: timestamp, want: "2024-06-13"},
+	}
+	for _, test := range tests {
+		f := newTestFormatter(t, test.timezone, test.utc, test.iso, test.now)
+		if got := f.FormatUploadTime(test.uploadDate, test.timestamp); got != test.want {
+			t.Errorf("FormatUploadTime(%q, %d) in %v (iso=%v) at %v = %q, want %q",
+				test.uploadDate, test.timestamp, f.Location, test.iso, test.now, got, test.want)
+		}
+	}
+}
+
+func TestIsNew(t *testing.T) {
+	// Midnight of 2024-06-13 is 2024-06-12 22:00 UTC in Berlin and 2024-06-13 04:00 UTC in New York
+	now := time.Date(2024, 6, 14, 2, 0, 0, 0, time.UTC)
+	tests := []struct {
+		timezone   string
+		now        time.Time
+		uploadDate string
+		timestamp  int64
+		want       bool
+	}{
+		{timezone: "Europe/Berlin", now: now, uploadDate: "20240613", want: false},
+		{timezone: "America/New_York", now: now, uploadDate: "20240613", want: true},
+		{timezone: "Europe/Berlin", now: time.Date(2024, 6, 13, 22, 0, 0, 0, time.UTC), uploadDate: "20240613", want: true},
+		{timezone: "Europe/Berlin", now: time.Date(2024, 6, 13, 22, 0, 1, 0, time.UTC), uploadDate: "20240613", want: false},
+		// Timestamp takes precedence over the upload date
+		{timezone: "Europe/Berlin", now: now, uploadDate: "20240613", timestamp: time.Date(2024, 6, 13, 12, 0, 0, 0, time.UTC).Unix(), want: true},
+		{timezone: "America/New_York", now: now, uploadDate: "20240613", timestamp: time.Date(2024, 6, 12, 12, 0, 0, 0, time.UTC).Unix(), want: false},
+		{timezone: "Europe/Berlin", now: now, uploadDate: "unknown", want: false},
+	}
+	for _, test := range tests {
+		f := newTestFormatter(t, test.timezone, false, false, test.now)
+		if got := f.IsNew(test.uploadDate, test.timestamp, 24*time.Hour); got != test.want {
+			t.Errorf("IsNew(%q, %d, 24h) in %s at %v = %v, want %v", test.uploadDate, test.timestamp, test.timezone, test.now, got, test.want)
+		}
+	}
+}
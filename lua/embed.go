@@ -0,0 +1,18 @@
+package lua
+
+import "embed"
+
+const SaveWatchedTimeScriptName = "mpv-customstart.lua"
+
+//go:embed mpv-customstart.lua
+var scripts embed.FS
+
+// SaveWatchedTimeScript returns bundled mpv script which saves watched time of the video on exit
+func SaveWatchedTimeScript() []byte {
+	script, err := scripts.ReadFile(SaveWatchedTimeScriptName)
+	if err != nil {
+		// Can't happen, file is embedded at compile time
+		panic(err)
+	}
+	return script
+}
@@ -1,8 +1,11 @@
 local mp = require 'mp'
+local utils = require 'mp.utils'
 local os = require('os')
 
 -- seconds amount watched during current session
 local watch_time = 0
+-- full video duration in seconds. Unknown for live streams
+local duration = nil
 
 -- save_watch_time captures how many seconds user watched youtube video
 -- and sums it with seconds watched previously and saves to file defined in WATCHED_FILE_NAME env variable
@@ -13,6 +16,10 @@ local watch_time = 0
 local function save_watch_time()
     local path = mp.get_property('path')
     local watched_file = os.getenv("WATCHED_FILE_NAME")
+    -- script installed into mpv scripts folder runs for every video, not only started by wtt-youtube-organizer
+    if watched_file == nil then
+        return
+    end
     -- Seconds amount watched during previous session and saved to watched_file
     local saved_watched_seconds = 0
     if os.getenv("WATCHED_SECONDS") ~= nil then
@@ -21,10 +28,31 @@ local function save_watch_time()
     local total_watched_seconds = watch_time + saved_watched_seconds
     mp.msg.info("Save watched seconds of "..total_watched_seconds)
 
-    local file = io.open(watched_file, 'w')
-    if file then
-        file:write(total_watched_seconds)
-        file:close()
+    local watched = { seconds = total_watched_seconds, duration = 0, percent = 0 }
+    if duration and duration > 0 then
+        watched.duration = math.floor(duration)
+        watched.percent = math.min(100, total_watched_seconds * 100 / duration)
+    end
+    local content = utils.format_json(watched)
+
+    -- write to temp file and rename it, so wtt-youtube-organizer never reads partially written file
+    local tmp_file = watched_file..".tmp"..utils.getpid()
+    local file = io.open(tmp_file, 'w')
+    if not file then
+        mp.msg.error("Failed to open "..tmp_file)
+        return
+    end
+    file:write(content)
+    file:close()
+    -- rename doesn't replace existing file on windows, so remove it and try again
+    local ok, err = os.rename(tmp_file, watched_file)
+    if not ok then
+        os.remove(watched_file)
+        ok, err = os.rename(tmp_file, watched_file)
+    end
+    if not ok then
+        mp.msg.error("Failed to save watched time to "..watched_file..": "..tostring(err))
+        os.remove(tmp_file)
     end
 end
 
@@ -35,5 +63,10 @@ mp.observe_property("time-pos", "number", function(_, time)
     end
 end)
 
+mp.observe_property("duration", "number", function(_, value)
+    if value then
+      duration = value
+    end
+end)
 
 mp.register_event('shutdown', save_watch_time)
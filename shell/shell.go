@@ -21,7 +21,8 @@ func ExecuteScript(command string, args ...string) *ExecScriptOut {
 	cmd := exec.Command(command, args...)
 	cmd.Env = os.Environ()
 
-	fmt.Printf("Execute: %s %s\n", command, strings.Join(args, " "))
+	// stderr keeps stdout clean for machine readable output, eg. show --output json
+	fmt.Fprintf(os.Stderr, "Execute: %s %s\n", command, strings.Join(args, " "))
 
 	// Set output to Byte Buffers
 	if cmd.Stdout != nil || cmd.Stderr != nil {
@@ -0,0 +1,205 @@
+package utils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TarGzFolder packs all regular files of srcDir into archivePath.
+// skip is called with path relative to srcDir and allows to exclude files or whole folders
+func TarGzFolder(srcDir string, archivePath string, skip func(relPath string) bool) (int, error) {
+	archive, err := os.Create(archivePath)
+	if err != nil {
+		return 0, fmt.Errorf("error creating archive %s: %v", archivePath, err)
+	}
+	defer archive.Close()
+	gzipWriter := gzip.NewWriter(archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	files := 0
+	err = filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+		if skip != nil && skip(relPath) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relPath)
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if _, err := io.Copy(tarWriter, file); err != nil {
+			return err
+		}
+		files++
+		return nil
+	})
+	if err != nil {
+		return files, fmt.Errorf("error archiving %s: %v", srcDir, err)
+	}
+	if err := tarWriter.Close(); err != nil {
+		return files, fmt.Errorf("error writing archive %s: %v", archivePath, err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return files, fmt.Errorf("error writing archive %s: %v", archivePath, err)
+	}
+	// Close reports failed flush of gzip and tar trailers to disk
+	if err := archive.Close(); err != nil {
+		return files, fmt.Errorf("error writing archive %s: %v", archivePath, err)
+	}
+	return files, nil
+}
+
+// UntarGz extracts regular files from archivePath into dstDir overwriting existing ones.
+// Archive is extracted into a temp folder next to dstDir together with hard links of existing files,
+// which replaces dstDir only when everything succeeded, so broken archive never leaves half imported dstDir
+func UntarGz(archivePath string, dstDir string) (int, error) {
+	dstDir = filepath.Clean(dstDir)
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dstDir), "."+filepath.Base(dstDir)+".import-")
+	if err != nil {
+		return 0, fmt.Errorf("error creating temp folder for %s: %v", dstDir, err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	files, err := extractTarGz(archivePath, tmpDir)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := os.Stat(dstDir); os.IsNotExist(err) {
+		if err := os.Rename(tmpDir, dstDir); err != nil {
+			return 0, fmt.Errorf("error moving imported files to %s: %v", dstDir, err)
+		}
+		return files, nil
+	}
+	if err := linkMissing(dstDir, tmpDir); err != nil {
+		return 0, fmt.Errorf("error keeping existing files of %s: %v", dstDir, err)
+	}
+	backupDir := tmpDir + ".old"
+	if err := os.Rename(dstDir, backupDir); err != nil {
+		return 0, fmt.Errorf("error replacing %s: %v", dstDir, err)
+	}
+	if err := os.Rename(tmpDir, dstDir); err != nil {
+		if restoreErr := os.Rename(backupDir, dstDir); restoreErr != nil {
+			return 0, fmt.Errorf("error replacing %s: %v, previous files are left in %s", dstDir, err, backupDir)
+		}
+		return 0, fmt.Errorf("error replacing %s: %v", dstDir, err)
+	}
+	if err := os.RemoveAll(backupDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to remove previous files in %s: %v\n", backupDir, err)
+	}
+	return files, nil
+}
+
+func extractTarGz(archivePath string, dstDir string) (int, error) {
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return 0, fmt.Errorf("error opening archive %s: %v", archivePath, err)
+	}
+	defer archive.Close()
+	gzipReader, err := gzip.NewReader(archive)
+	if err != nil {
+		return 0, fmt.Errorf("error reading archive %s: %v", archivePath, err)
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+
+	files := 0
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return files, fmt.Errorf("error reading archive %s: %v", archivePath, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		target := filepath.Join(dstDir, filepath.FromSlash(header.Name))
+		// Never write outside of dstDir, eg. for entries like ../../.bashrc
+		if !strings.HasPrefix(target, filepath.Clean(dstDir)+string(os.PathSeparator)) {
+			return files, fmt.Errorf("archive entry %s points outside of %s", header.Name, dstDir)
+		}
+		CreateFolderIfNoExist(filepath.Dir(target))
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode).Perm())
+		if err != nil {
+			return files, fmt.Errorf("error creating %s: %v", target, err)
+		}
+		_, err = io.Copy(file, tarReader)
+		file.Close()
+		if err != nil {
+			return files, fmt.Errorf("error extracting %s: %v", target, err)
+		}
+		files++
+	}
+}
+
+// linkMissing hard links files of srcDir which are missing in dstDir.
+// Links keep the same files, so locks held on them by running processes stay valid after the swap
+func linkMissing(srcDir string, dstDir string) error {
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		src := filepath.Join(srcDir, entry.Name())
+		dst := filepath.Join(dstDir, entry.Name())
+		dstInfo, err := os.Lstat(dst)
+		if err == nil {
+			// Archive version of the file wins
+			if entry.IsDir() && dstInfo.IsDir() {
+				if err := linkMissing(src, dst); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+		if entry.IsDir() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			if err := os.Mkdir(dst, info.Mode().Perm()); err != nil {
+				return err
+			}
+			if err := linkMissing(src, dst); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.Link(src, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	CreateFolderIfNoExist(filepath.Dir(path))
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestUntarGzMergesIntoExistingFolder(t *testing.T) {
+	dir := t.TempDir()
+	srcDir := filepath.Join(dir, "src")
+	writeFile(t, filepath.Join(srcDir, "watched", "a"), "new a")
+	writeFile(t, filepath.Join(srcDir, "filter_sets.json"), "{}")
+	archivePath := filepath.Join(dir, "state.tar.gz")
+	if files, err := TarGzFolder(srcDir, archivePath, nil); err != nil || files != 2 {
+		t.Fatalf("TarGzFolder() = %d, %v", files, err)
+	}
+
+	dstDir := filepath.Join(dir, "dst")
+	writeFile(t, filepath.Join(dstDir, "watched", "a"), "old a")
+	writeFile(t, filepath.Join(dstDir, "watched", "b"), "old b")
+	if files, err := UntarGz(archivePath, dstDir); err != nil || files != 2 {
+		t.Fatalf("UntarGz() = %d, %v", files, err)
+	}
+	if got := readFile(t, filepath.Join(dstDir, "watched", "a")); got != "new a" {
+		t.Errorf("watched/a = %q, want archive version", got)
+	}
+	if got := readFile(t, filepath.Join(dstDir, "watched", "b")); got != "old b" {
+		t.Errorf("watched/b = %q, want existing file kept", got)
+	}
+	if got := readFile(t, filepath.Join(dstDir, "filter_sets.json")); got != "{}" {
+		t.Errorf("filter_sets.json = %q, want archive version", got)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("temp folders left next to dst: %v", entries)
+	}
+}
+
+func TestUntarGzKeepsFolderOnBrokenArchive(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "evil.tar.gz")
+	archive, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzipWriter := gzip.NewWriter(archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, name := range []string{"watched/a", "../escape"} {
+		if err := tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 1, Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write([]byte("x")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tarWriter.Close()
+	gzipWriter.Close()
+	archive.Close()
+
+	dstDir := filepath.Join(dir, "dst")
+	writeFile(t, filepath.Join(dstDir, "watched", "a"), "old a")
+	if _, err := UntarGz(archivePath, dstDir); err == nil {
+		t.Fatal("UntarGz() succeeded for entry outside of the folder")
+	}
+	if got := readFile(t, filepath.Join(dstDir, "watched", "a")); got != "old a" {
+		t.Errorf("watched/a = %q, want it untouched", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape")); !os.IsNotExist(err) {
+		t.Errorf("entry outside of the folder was written")
+	}
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+type BuildSetting struct {
+	Key   string
+	Value string
+}
+
+// BuildSettings returns go version and vcs info stamped into the binary by go build
+func BuildSettings() []BuildSetting {
+	settings := []BuildSetting{{Key: "go", Value: runtime.Version() + " " + runtime.GOOS + "/" + runtime.GOARCH}}
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return settings
+	}
+	for _, setting := range buildInfo.Settings {
+		if strings.HasPrefix(setting.Key, "vcs.") {
+			settings = append(settings, BuildSetting{Key: setting.Key, Value: setting.Value})
+		}
+	}
+	return settings
+}
+
+// BuildCommit returns short vcs revision or unknown when binary was built without vcs info
+func BuildCommit() string {
+	for _, setting := range BuildSettings() {
+		if setting.Key == "vcs.revision" && len(setting.Value) >= 7 {
+			return setting.Value[:7]
+		}
+	}
+	return "unknown"
+}
@@ -1,9 +1,46 @@
 package utils
 
-import "strings"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 const MainCommand = "wtt-youtube-organizer"
 
 var FormatExample *strings.Replacer = strings.NewReplacer(
 	"{cmd}", MainCommand,
 )
+
+// ParseDuration extends time.ParseDuration with days, eg. 180d or 1d12h.
+// Only positive durations are accepted, since they are used as age thresholds
+func ParseDuration(value string) (time.Duration, error) {
+	duration, err := parseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("invalid duration %s: must be positive", value)
+	}
+	return duration, nil
+}
+
+func parseDuration(value string) (time.Duration, error) {
+	if days, rest, found := strings.Cut(value, "d"); found {
+		daysNum, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %s: %v", value, err)
+		}
+		duration := time.Duration(daysNum) * 24 * time.Hour
+		if rest == "" {
+			return duration, nil
+		}
+		restDuration, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, err
+		}
+		return duration + restDuration, nil
+	}
+	return time.ParseDuration(value)
+}
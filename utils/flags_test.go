@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "180d", want: 180 * 24 * time.Hour},
+		{value: "1d12h", want: 36 * time.Hour},
+		{value: "1d30m", want: 24*time.Hour + 30*time.Minute},
+		{value: "720h", want: 720 * time.Hour},
+		{value: "90m", want: 90 * time.Minute},
+	}
+	for _, test := range tests {
+		got, err := ParseDuration(test.value)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) returned error: %v", test.value, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", test.value, got, test.want)
+		}
+	}
+}
+
+func TestParseDurationInvalid(t *testing.T) {
+	for _, value := range []string{"", "d", "xd", "1dx", "1w", "-5d", "0d", "0", "-1h", "1d-25h"} {
+		if got, err := ParseDuration(value); err == nil {
+			t.Errorf("ParseDuration(%q) = %v, want error", value, got)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LoadJSON reads json file into v. Not existing file error is returned as is to check it with os.IsNotExist
+func LoadJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	return nil
+}
+
+// SaveJSON writes v as indented json atomically creating parent folder if needed
+func SaveJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling %s: %v", path, err)
+	}
+	CreateFolderIfNoExist(filepath.Dir(path))
+	return WriteFileAtomic(path, data, 0644)
+}
@@ -0,0 +1,39 @@
+//go:build !windows
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// ErrLocked is returned by TryLock when another process already holds the lock
+var ErrLocked = errors.New("lock is held by another process")
+
+type FileLock struct {
+	file *os.File
+}
+
+// TryLock takes an exclusive non blocking lock on lockPath.
+// Lock is released by the kernel when the process exits, so crashed runs never leave stale locks
+func TryLock(lockPath string) (*FileLock, error) {
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening lock file %s: %v", lockPath, err)
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, ErrLocked
+		}
+		return nil, fmt.Errorf("error locking %s: %v", lockPath, err)
+	}
+	return &FileLock{file: file}, nil
+}
+
+func (l *FileLock) Unlock() error {
+	defer l.file.Close()
+	return syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// ErrLocked is returned by TryLock when another process already holds the lock
+var ErrLocked = errors.New("lock is held by another process")
+
+// syscall package has no file locking on windows, so LockFileEx is called directly
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+const (
+	lockfileFailImmediately = 0x1
+	lockfileExclusiveLock   = 0x2
+	errorLockViolation      = syscall.Errno(33)
+)
+
+type FileLock struct {
+	file *os.File
+}
+
+// TryLock takes an exclusive non blocking lock on lockPath.
+// Lock is released by the kernel when the process exits, so crashed runs never leave stale locks
+func TryLock(lockPath string) (*FileLock, error) {
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening lock file %s: %v", lockPath, err)
+	}
+	var overlapped syscall.Overlapped
+	ret, _, err := procLockFileEx.Call(file.Fd(), lockfileExclusiveLock|lockfileFailImmediately, 0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+	if ret == 0 {
+		file.Close()
+		if errors.Is(err, errorLockViolation) {
+			return nil, ErrLocked
+		}
+		return nil, fmt.Errorf("error locking %s: %v", lockPath, err)
+	}
+	return &FileLock{file: file}, nil
+}
+
+func (l *FileLock) Unlock() error {
+	defer l.file.Close()
+	var overlapped syscall.Overlapped
+	ret, _, err := procUnlockFileEx.Call(l.file.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+	if ret == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package youtubeparser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"wtt-youtube-organizer/config"
+	"wtt-youtube-organizer/shell"
+	"wtt-youtube-organizer/utils"
+)
+
+// Raw yt-dlp output of the channel listing. Cached raw rather than parsed,
+// so parser changes and --raw apply to cached listing as well
+const ChannelCacheFileName = "channel_videos.jsonl"
+
+func GetChannelCachePath() string {
+	return filepath.Join(config.GetCacheDir(), ChannelCacheFileName)
+}
+
+// fetchChannelListing returns yt-dlp output of WTT channel listing.
+// Cached listing is reused while it's younger than filters.CacheTTL unless filters.Refresh is set.
+// In offline mode cached listing is used regardless of its age
+func fetchChannelListing(filters *Filters) (string, error) {
+	cachePath := GetChannelCachePath()
+	if filters.Offline {
+		data, err := os.ReadFile(cachePath)
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("no cached channel videos, run any command without --offline first")
+		}
+		if err != nil {
+			return "", fmt.Errorf("error reading channel cache %s: %v", cachePath, err)
+		}
+		return string(data), nil
+	}
+	if !filters.Refresh && filters.CacheTTL > 0 {
+		if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < filters.CacheTTL {
+			data, err := os.ReadFile(cachePath)
+			if err == nil {
+				return string(data), nil
+			}
+			fmt.Fprintf(os.Stderr, "Failed to read channel cache %s, fetching again: %v\n", cachePath, err)
+		}
+	}
+	out := shell.ExecuteScript("yt-dlp", "-j", "--flat-playlist", "--playlist-items", "1-200", "--extractor-args", "youtubetab:approximate_date", "https://www.youtube.com/@WTTGlobal/videos")
+	if out.Err != "" {
+		return "", fmt.Errorf("error executing shell command: %s", out.Err)
+	}
+	// Failed cache write only makes the next run slower
+	utils.CreateFolderIfNoExist(config.GetCacheDir())
+	if err := utils.WriteFileAtomic(cachePath, []byte(out.Out), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save channel cache: %v\n", err)
+	}
+	return out.Out, nil
+}
@@ -0,0 +1,108 @@
+package youtubeparser
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// CorpusEntry is a single observed video title with the parse result it must produce.
+// Expected is nil for titles which must be rejected by the parser, eg. interviews or highlights
+type CorpusEntry struct {
+	Title    string     `json:"title"`
+	Expected *NameParts `json:"expected"`
+}
+
+type CorpusMismatch struct {
+	Entry  CorpusEntry
+	Actual *NameParts
+}
+
+// NewCorpusEntry records how current parser handles the title
+func NewCorpusEntry(title string) CorpusEntry {
+	parsed, err := NameParts{}.Parse(title)
+	if err != nil {
+		return CorpusEntry{Title: title}
+	}
+	return CorpusEntry{Title: title, Expected: parsed}
+}
+
+func LoadCorpus(corpusPath string) ([]CorpusEntry, error) {
+	file, err := os.Open(corpusPath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening corpus %s: %v", corpusPath, err)
+	}
+	defer file.Close()
+
+	var entries []CorpusEntry
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry CorpusEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("error parsing corpus %s line %d: %v", corpusPath, lineNum, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading corpus %s: %v", corpusPath, err)
+	}
+	return entries, nil
+}
+
+// AppendCorpus adds entries to the end of corpus file. Titles already present in corpus are skipped
+func AppendCorpus(corpusPath string, entries []CorpusEntry) (int, error) {
+	known := make(map[string]bool)
+	if _, err := os.Stat(corpusPath); err == nil {
+		existing, err := LoadCorpus(corpusPath)
+		if err != nil {
+			return 0, err
+		}
+		for _, entry := range existing {
+			known[entry.Title] = true
+		}
+	}
+
+	file, err := os.OpenFile(corpusPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("error opening corpus %s: %v", corpusPath, err)
+	}
+	defer file.Close()
+
+	added := 0
+	for _, entry := range entries {
+		if known[entry.Title] {
+			continue
+		}
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return added, fmt.Errorf("error marshalling corpus entry %s: %v", entry.Title, err)
+		}
+		if _, err := file.Write(append(line, '\n')); err != nil {
+			return added, fmt.Errorf("error writing corpus %s: %v", corpusPath, err)
+		}
+		known[entry.Title] = true
+		added++
+	}
+	return added, nil
+}
+
+// VerifyCorpus parses every corpus title again and returns the ones which do not match expected result
+func VerifyCorpus(entries []CorpusEntry) []CorpusMismatch {
+	var mismatches []CorpusMismatch
+	for _, entry := range entries {
+		actual := NewCorpusEntry(entry.Title).Expected
+		if !reflect.DeepEqual(actual, entry.Expected) {
+			mismatches = append(mismatches, CorpusMismatch{Entry: entry, Actual: actual})
+		}
+	}
+	return mismatches
+}
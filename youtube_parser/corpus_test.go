@@ -0,0 +1,16 @@
+package youtubeparser
+
+import "testing"
+
+func TestTitleCorpus(t *testing.T) {
+	entries, err := LoadCorpus("testdata/title_corpus.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("title corpus is empty")
+	}
+	for _, mismatch := range VerifyCorpus(entries) {
+		t.Errorf("%q parsed as %+v, expected %+v", mismatch.Entry.Title, mismatch.Actual, mismatch.Entry.Expected)
+	}
+}
@@ -0,0 +1,62 @@
+package youtubeparser
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Round name shown instead of rounds hidden by spoiler free mode
+const HiddenRound = "Later rounds"
+
+// Rounds from the earliest to the latest stage of the tournament, qualification goes before the main draw
+var roundOrder = []string{"Q1", "Q2", "Q3", "R128", "R64", "R32", "R16", "QF", "SF", "F"}
+
+// RoundIndex returns position of the round in the tournament, eg. 0 for Q1 and 9 for F.
+// Unknown rounds go after the final
+func RoundIndex(round string) int {
+	roundInd := slices.Index(roundOrder, strings.ToUpper(round))
+	if roundInd == -1 {
+		return len(roundOrder)
+	}
+	return roundInd
+}
+
+func ValidateSpoilerStage(stage string) error {
+	if !slices.Contains(roundOrder, strings.ToUpper(stage)) {
+		return fmt.Errorf("unknown spoiler stage %s, expected one of %s", stage, strings.Join(roundOrder, ", "))
+	}
+	return nil
+}
+
+// IsSpoilerRound reports whether round comes after the last stage user wants to see.
+// Unknown rounds are treated as spoilers, because it's safer to hide them
+func IsSpoilerRound(round string, lastVisibleStage string) bool {
+	stageInd := slices.Index(roundOrder, strings.ToUpper(lastVisibleStage))
+	roundInd := slices.Index(roundOrder, strings.ToUpper(round))
+	if stageInd == -1 {
+		return false
+	}
+	return roundInd == -1 || roundInd > stageInd
+}
+
+// DisplayRound returns the round name respecting spoiler free mode
+func (v *YoutubeVideo) DisplayRound(filters *Filters) string {
+	if filters.NoSpoilers && IsSpoilerRound(v.Round, filters.SpoilerStage) {
+		return HiddenRound
+	}
+	return v.Round
+}
+
+// DisplayTitle returns original title or the title built from parsed parts when spoilers are hidden,
+// since original title always contains the round
+func (v *YoutubeVideo) DisplayTitle(filters *Filters) string {
+	if !filters.NoSpoilers {
+		return v.Title
+	}
+	title := fmt.Sprintf("%s | %s %s | %s", v.Players, v.Gender, v.DisplayRound(filters), v.Tournament)
+	if v.FullMatch {
+		title = "FULL MATCH | " + title
+	}
+	return title
+}
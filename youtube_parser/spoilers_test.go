@@ -0,0 +1,33 @@
+package youtubeparser
+
+import "testing"
+
+func TestIsSpoilerRound(t *testing.T) {
+	tests := []struct {
+		round string
+		stage string
+		want  bool
+	}{
+		{round: "Q1", stage: "QF", want: false},
+		{round: "q2", stage: "R32", want: false},
+		{round: "R16", stage: "QF", want: false},
+		{round: "QF", stage: "QF", want: false},
+		{round: "SF", stage: "QF", want: true},
+		{round: "F", stage: "qf", want: true},
+		{round: "Group", stage: "QF", want: true},
+		{round: "F", stage: "unknown", want: false},
+	}
+	for _, test := range tests {
+		if got := IsSpoilerRound(test.round, test.stage); got != test.want {
+			t.Errorf("IsSpoilerRound(%q, %q) = %v, want %v", test.round, test.stage, got, test.want)
+		}
+	}
+}
+
+func TestDisplayRoundKeepsQualification(t *testing.T) {
+	video := &YoutubeVideo{Round: "Q1"}
+	filters := &Filters{NoSpoilers: true, SpoilerStage: "QF"}
+	if got := video.DisplayRound(filters); got != "Q1" {
+		t.Errorf("DisplayRound() = %q, want Q1", got)
+	}
+}
@@ -0,0 +1,40 @@
+package youtubeparser
+
+import "time"
+
+// VideoRow is the machine readable form of YoutubeVideo used by show --output and serve.
+// Title and round respect --noSpoilers the same way as text output, duration is 0 in this mode
+// since it tells how long the match was
+type VideoRow struct {
+	ID              string `json:"id"`
+	URL             string `json:"url"`
+	Title           string `json:"title"`
+	UploadDate      string `json:"upload_date"`
+	Timestamp       int64  `json:"timestamp"`
+	DurationSeconds int64  `json:"duration_seconds"`
+	FullMatch       bool   `json:"full_match"`
+	Players         string `json:"players"`
+	Gender          string `json:"gender"`
+	Round           string `json:"round"`
+	Tournament      string `json:"tournament"`
+}
+
+func NewVideoRow(video *YoutubeVideo, filters *Filters) VideoRow {
+	duration := video.Duration
+	if filters.NoSpoilers {
+		duration = 0
+	}
+	return VideoRow{
+		ID:              video.ID,
+		URL:             video.URL,
+		Title:           video.DisplayTitle(filters),
+		UploadDate:      video.UploadDate,
+		Timestamp:       video.Timestamp,
+		DurationSeconds: int64(duration / time.Second),
+		FullMatch:       video.FullMatch,
+		Players:         video.Players,
+		Gender:          video.Gender,
+		Round:           video.DisplayRound(filters),
+		Tournament:      video.Tournament,
+	}
+}
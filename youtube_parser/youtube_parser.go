@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"os"
+	"regexp"
 	"slices"
 	"strings"
 	"time"
@@ -12,13 +14,16 @@ import (
 )
 
 type YoutubeVideoInt struct {
+	ID             string `json:"id"`
 	URL            string `json:"url"`
 	Title          string `json:"title"`
 	UploadDate     string `json:"upload_date"`
+	Timestamp      int64  `json:"timestamp"`
 	DurationString string `json:"duration_string"`
 }
 
 type YoutubeVideo struct {
+	ID         string
 	URL        string
 	FullMatch  bool
 	Players    string
@@ -26,16 +31,17 @@ type YoutubeVideo struct {
 	Round      string
 	Tournament string
 	UploadDate string
+	Timestamp  int64 // approximate upload unix time, 0 when unknown
 	Duration   time.Duration
 	Title      string
 }
 
 type NameParts struct {
-	FullMatch  bool
-	Players    string
-	Gender     string
-	Round      string
-	Tournament string
+	FullMatch  bool   `json:"full_match"`
+	Players    string `json:"players"`
+	Gender     string `json:"gender"`
+	Round      string `json:"round"`
+	Tournament string `json:"tournament"`
 }
 
 type Filters struct {
@@ -46,23 +52,66 @@ type Filters struct {
 	Full              bool
 	TodayOnly         bool
 	DisableAllFilters bool
+	Raw               bool
+	// Gender filter used to default to MS. Set when --gender was not passed to warn about the change
+	// TODO remove once users had time to notice new default
+	GenderNotSet      bool
+	NoSpoilers        bool
+	SpoilerStage      string
+	ExcludeFilter     []string
+	ExcludeTournament []string
+	ExcludePlayer     []string
+	CacheTTL          time.Duration
+	Refresh           bool
+	Offline           bool
 }
 
+// ErrOffline is returned for operations which need network when --offline is set
+var ErrOffline = errors.New("not available with --offline")
+
 type WatchHistory struct {
 	Urls map[string]*YoutubeVideo
 }
 
 func FilterWttVideos(filters *Filters) []*YoutubeVideo {
-	out := shell.ExecuteScript("yt-dlp", "-j", "--flat-playlist", "--playlist-items", "1-200", "--extractor-args", "youtubetab:approximate_date", "https://www.youtube.com/@WTTGlobal/videos")
-	if out.Err != "" {
-		log.Fatalf("Error executing shell command: %s", out.Err)
+	videos, err := FetchWttVideos(filters)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return videos
+}
+
+// FetchWttVideos does the same as FilterWttVideos but returns an error
+// instead of exiting, so callers like the pipeline can decide how to fail
+func FetchWttVideos(filters *Filters) ([]*YoutubeVideo, error) {
+	listing, err := fetchChannelListing(filters)
+	if err != nil {
+		return nil, err
+	}
+	if filters.GenderNotSet && !filters.DisableAllFilters {
+		fmt.Fprintln(os.Stderr, "Warning: --gender no longer defaults to MS and matches of all genders are shown. Pass --gender MS to keep old behavior")
+	}
+	videos, err := parseYtlpOutput(listing, filters.Raw)
+	if err != nil {
+		return nil, err
 	}
-	videos := parseYtlpOutput(out.Out)
-	var finalVideos []*YoutubeVideo
 	var watchHistory *WatchHistory
-	if !filters.ShowWatched {
-		watchHistory = GetWatchHistory()
+	// Watch history lives on youtube, so offline watched videos are shown too
+	if filters.Offline && !filters.ShowWatched && !filters.DisableAllFilters {
+		fmt.Fprintln(os.Stderr, "Warning: watched videos are not hidden with --offline")
+	} else if !filters.ShowWatched && !filters.DisableAllFilters {
+		watchHistory, err = GetWatchHistory()
+		if err != nil {
+			return nil, err
+		}
 	}
+	return filterVideos(videos, filters, watchHistory)
+}
+
+// filterVideos applies filters to parsed videos and reverses them, so the newest video is the last one.
+// watchHistory is used only when watched videos are hidden, nil when it's not available, eg. offline
+func filterVideos(videos []*YoutubeVideo, filters *Filters, watchHistory *WatchHistory) ([]*YoutubeVideo, error) {
+	var finalVideos []*YoutubeVideo
 	for i := len(videos) - 1; i >= 0; i-- {
 		video := videos[i]
 		// Just add videos when filters are disabled
@@ -72,9 +121,9 @@ func FilterWttVideos(filters *Filters) []*YoutubeVideo {
 		}
 		isTodayDate, err := isToday(video.UploadDate)
 		if err != nil {
-			log.Default().Fatalln(err)
+			return nil, err
 		}
-		if !filters.ShowWatched && watchHistory.Contains(video.URL) {
+		if !filters.ShowWatched && watchHistory != nil && watchHistory.Contains(video.URL) {
 			continue
 		}
 
@@ -84,7 +133,16 @@ func FilterWttVideos(filters *Filters) []*YoutubeVideo {
 		if len(filters.Filter) > 0 && !strings.Contains(strings.ToLower(video.Title), strings.ToLower(filters.Filter)) {
 			continue
 		}
-		if len(filters.Gender) > 0 && !strings.EqualFold(video.Gender, filters.Gender) {
+		if containsAnyFold(video.Title, filters.ExcludeFilter) {
+			continue
+		}
+		if containsAnyFold(video.Tournament, filters.ExcludeTournament) {
+			continue
+		}
+		if containsAnyFold(video.Players, filters.ExcludePlayer) {
+			continue
+		}
+		if len(filters.Gender) > 0 && !strings.EqualFold(filters.Gender, "all") && !strings.EqualFold(video.Gender, filters.Gender) {
 			continue
 		}
 		if filters.Full && !video.FullMatch {
@@ -95,23 +153,69 @@ func FilterWttVideos(filters *Filters) []*YoutubeVideo {
 		}
 		finalVideos = append(finalVideos, video)
 	}
-	return finalVideos
+	return finalVideos, nil
+}
+
+// FetchLatestVideos reads only count latest entries of WTT channel.
+// Returns parsed videos together with amount of fetched entries to see how many titles failed to parse
+func FetchLatestVideos(count int) ([]*YoutubeVideo, int, error) {
+	out := shell.ExecuteScript("yt-dlp", "-j", "--flat-playlist", "--playlist-items", fmt.Sprintf("1-%d", count), "--extractor-args", "youtubetab:approximate_date", "https://www.youtube.com/@WTTGlobal/videos")
+	if out.Err != "" {
+		return nil, 0, fmt.Errorf("error executing shell command: %s", out.Err)
+	}
+	fetched := 0
+	for _, line := range strings.Split(out.Out, "\n") {
+		if strings.HasPrefix(line, "{") {
+			fetched++
+		}
+	}
+	videos, err := parseYtlpOutput(out.Out, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	return videos, fetched, nil
 }
 
-func GetWatchHistory() *WatchHistory {
+// GetYouTubeId extracts video id from youtube url
+// https://www.youtube.com/watch?v=OdXQDJOQ27w -> OdXQDJOQ27w
+func GetYouTubeId(videoUrl string) (string, error) {
+	re := regexp.MustCompile(`(?:v=|/)([0-9A-Za-z_-]{11}).*`)
+	matches := re.FindStringSubmatch(videoUrl)
+	if len(matches) < 2 {
+		return "", fmt.Errorf("invalid YouTube URL")
+	}
+	return matches[1], nil
+}
+
+// containsAnyFold reports whether value contains any of terms ignoring the case
+func containsAnyFold(value string, terms []string) bool {
+	for _, term := range terms {
+		if len(term) > 0 && strings.Contains(strings.ToLower(value), strings.ToLower(term)) {
+			return true
+		}
+	}
+	return false
+}
+
+func GetWatchHistory() (*WatchHistory, error) {
 	out := shell.ExecuteScript("yt-dlp", "-j", "--cookies-from-browser", "CHROME", "--flat-playlist", "--playlist-items", "1-500", "--extractor-args", "youtubetab:approximate_date", "https://www.youtube.com/feed/history")
 	if out.Err != "" {
-		log.Fatalf("Error executing shell command: %s", out.Err)
+		return nil, fmt.Errorf("error executing shell command: %s", out.Err)
+	}
+	videos, err := parseYtlpOutput(out.Out, false)
+	if err != nil {
+		return nil, err
 	}
-	videos := parseYtlpOutput(out.Out)
 	watchHistory := NewWatchHistory()
 	for _, video := range videos {
 		watchHistory.AddVideo(video)
 	}
-	return watchHistory
+	return watchHistory, nil
 }
 
-func parseYtlpOutput(ytDlpOutput string) []*YoutubeVideo {
+// parseYtlpOutput skips videos with unparseable titles unless raw is set.
+// Raw videos keep full title as players and go to the Unknown tournament
+func parseYtlpOutput(ytDlpOutput string, raw bool) ([]*YoutubeVideo, error) {
 	// Split the output into individual JSON objects
 	lines := strings.Split(ytDlpOutput, "\n")
 	var videos []*YoutubeVideo
@@ -126,7 +230,8 @@ func parseYtlpOutput(ytDlpOutput string) []*YoutubeVideo {
 		err := json.Unmarshal([]byte(line), &video)
 
 		if err != nil {
-			fmt.Printf("Error unmarshalling JSON: %v\n", err)
+			// stderr keeps stdout clean for machine readable output, eg. show --output json
+			fmt.Fprintf(os.Stderr, "Error unmarshalling JSON: %v\n", err)
 			continue // Skip this line if there's an error
 		}
 		// shorts don't have a duration and that's since we don't need shorts
@@ -134,6 +239,9 @@ func parseYtlpOutput(ytDlpOutput string) []*YoutubeVideo {
 			continue
 		}
 		titleParts, err := NameParts{}.Parse(video.Title)
+		if err != nil && raw {
+			titleParts, err = &NameParts{Players: video.Title, Tournament: "Unknown"}, nil
+		}
 		// Not interested in videos which are not parseable, eg. contain wrong title
 		if err != nil {
 			// Uncomment to print all errors that were failed to parse
@@ -142,11 +250,13 @@ func parseYtlpOutput(ytDlpOutput string) []*YoutubeVideo {
 		}
 		duration, err := parseDuration(video.DurationString)
 		if err != nil {
-			log.Fatalf("Failed to parse video: %v from %v", err, video)
+			return nil, fmt.Errorf("failed to parse video: %v from %v", err, video)
 		}
 		videoFinal := YoutubeVideo{
+			ID:         video.ID,
 			URL:        video.URL,
 			UploadDate: video.UploadDate,
+			Timestamp:  video.Timestamp,
 			FullMatch:  titleParts.FullMatch,
 			Players:    titleParts.Players,
 			Gender:     titleParts.Gender,
@@ -156,7 +266,7 @@ func parseYtlpOutput(ytDlpOutput string) []*YoutubeVideo {
 			Title:      video.Title}
 		videos = append(videos, &videoFinal)
 	}
-	return videos
+	return videos, nil
 }
 
 func isToday(dateStr string) (bool, error) {
@@ -0,0 +1,68 @@
+package youtubeparser
+
+import (
+	"slices"
+	"testing"
+)
+
+func testVideos() []*YoutubeVideo {
+	return []*YoutubeVideo{
+		{ID: "ms", URL: "https://www.youtube.com/watch?v=ms", Gender: "MS", Round: "F", UploadDate: "20240301"},
+		{ID: "ws", URL: "https://www.youtube.com/watch?v=ws", Gender: "WS", Round: "F", UploadDate: "20240301"},
+		{ID: "xd", URL: "https://www.youtube.com/watch?v=xd", Gender: "XD", Round: "SF", UploadDate: "20240301"},
+	}
+}
+
+func videoIds(videos []*YoutubeVideo) []string {
+	var ids []string
+	for _, video := range videos {
+		ids = append(ids, video.ID)
+	}
+	return ids
+}
+
+func TestFilterVideosGender(t *testing.T) {
+	tests := []struct {
+		name   string
+		gender string
+		want   []string
+	}{
+		{name: "default shows all genders", gender: "", want: []string{"xd", "ws", "ms"}},
+		{name: "all", gender: "all", want: []string{"xd", "ws", "ms"}},
+		{name: "all ignores case", gender: "ALL", want: []string{"xd", "ws", "ms"}},
+		{name: "men", gender: "MS", want: []string{"ms"}},
+		{name: "women", gender: "WS", want: []string{"ws"}},
+		{name: "women ignores case", gender: "ws", want: []string{"ws"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			videos, err := filterVideos(testVideos(), &Filters{Gender: test.gender, ShowWatched: true}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := videoIds(videos); !slices.Equal(got, test.want) {
+				t.Errorf("filterVideos() with gender %q = %v, want %v", test.gender, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFilterVideosWatched(t *testing.T) {
+	watchHistory := NewWatchHistory()
+	watchHistory.AddVideo(&YoutubeVideo{URL: "https://www.youtube.com/watch?v=ws"})
+	videos, err := filterVideos(testVideos(), &Filters{}, watchHistory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := videoIds(videos), []string{"xd", "ms"}; !slices.Equal(got, want) {
+		t.Errorf("filterVideos() with watch history = %v, want %v", got, want)
+	}
+	// Offline mode has no watch history
+	videos, err = filterVideos(testVideos(), &Filters{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := videoIds(videos), []string{"xd", "ws", "ms"}; !slices.Equal(got, want) {
+		t.Errorf("filterVideos() without watch history = %v, want %v", got, want)
+	}
+}